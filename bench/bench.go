@@ -0,0 +1,125 @@
+// Package bench measures how long each part of a solver takes and how much
+// memory it uses, and keeps a history of the measurements so that a change can
+// be compared against earlier runs.
+package bench
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+	"time"
+
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+// How often the heap is sampled while looking for its peak
+const SAMPLE_INTERVAL = time.Millisecond
+
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// Result is the measurement of one part of one day's solver
+type Result struct {
+	Day  int `json:"day"`
+	Part int `json:"part"`
+	// The SHA-256 of the input, since only runs over the same input can be
+	// compared
+	InputHash   string `json:"input_hash"`
+	Iterations  int    `json:"iterations"`
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp int64  `json:"allocs_per_op"`
+	BytesPerOp  int64  `json:"bytes_per_op"`
+	// The most heap in use at once during a single solve, in bytes
+	PeakHeap uint64    `json:"peak_heap"`
+	Time     time.Time `json:"time"`
+}
+
+// SetBenchTime sets how long each part is run for, like go test's -benchtime
+// flag (which it is)
+func SetBenchTime(d time.Duration) error {
+	testing.Init()
+	return flag.Set("test.benchtime", d.String())
+}
+
+// Measure benchmarks one part of a solver against input. The part is solved
+// once up front to make sure it succeeds and to find its peak heap usage, and
+// then as many times as the bench time allows.
+func Measure(s solver.Solver, day int, part int, input []byte) (Result, error) {
+	hash := sha256.Sum256(input)
+	res := Result{
+		Day:       day,
+		Part:      part,
+		InputHash: hex.EncodeToString(hash[:]),
+		Time:      time.Now().UTC(),
+	}
+
+	peak, err := measurePeakHeap(func() error {
+		_, err := s.Solve(part, bytes.NewReader(input))
+		return err
+	})
+	if err != nil {
+		return res, err
+	}
+	res.PeakHeap = peak
+
+	b := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s.Solve(part, bytes.NewReader(input))
+		}
+	})
+	res.Iterations = b.N
+	res.NsPerOp = b.NsPerOp()
+	res.AllocsPerOp = b.AllocsPerOp()
+	res.BytesPerOp = b.AllocedBytesPerOp()
+
+	return res, nil
+}
+
+// measurePeakHeap runs f while sampling the heap, and returns how far the heap
+// grew above what was in use beforehand. Sampling can miss peaks that last
+// less than SAMPLE_INTERVAL, so this is a lower bound.
+func measurePeakHeap(f func() error) (uint64, error) {
+	runtime.GC()
+	sample := []metrics.Sample{{Name: heapMetric}}
+	read := func() uint64 {
+		metrics.Read(sample)
+		return sample[0].Value.Uint64()
+	}
+	baseline := read()
+
+	done := make(chan struct{})
+	peakCh := make(chan uint64)
+	go func() {
+		peak := baseline
+		ticker := time.NewTicker(SAMPLE_INTERVAL)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				{
+					if heap := read(); heap > peak {
+						peak = heap
+					}
+				}
+			case <-done:
+				{
+					if heap := read(); heap > peak {
+						peak = heap
+					}
+					peakCh <- peak
+					return
+				}
+			}
+		}
+	}()
+
+	err := f()
+	close(done)
+	peak := <-peakCh
+
+	return peak - baseline, err
+}
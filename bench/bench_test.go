@@ -0,0 +1,78 @@
+package bench
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+func TestCompare(t *testing.T) {
+	previous := Result{NsPerOp: 1000, AllocsPerOp: 10, BytesPerOp: 100, PeakHeap: 0}
+	current := Result{NsPerOp: 1050, AllocsPerOp: 20, BytesPerOp: 50, PeakHeap: 10}
+
+	regressions := Compare(previous, current, 0.1)
+	got := make([]string, len(regressions))
+	for i, r := range regressions {
+		got[i] = r.Metric
+	}
+	// time is within the threshold, and fewer bytes is an improvement
+	if strings.Join(got, ",") != "allocs/op,peak heap" {
+		t.Errorf("got regressions in %v, want allocs/op and peak heap", got)
+	}
+	if change := regressions[0].Change(); change != 1 {
+		t.Errorf("allocs/op change = %v, want 1", change)
+	}
+}
+
+func TestHistoryPrevious(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results", "benchmarks.json")
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Record(Result{Day: 1, Part: 1, InputHash: "a", NsPerOp: 1})
+	h.Record(Result{Day: 1, Part: 1, InputHash: "b", NsPerOp: 2})
+	h.Record(Result{Day: 1, Part: 1, InputHash: "a", NsPerOp: 3})
+	h.Record(Result{Day: 1, Part: 2, InputHash: "a", NsPerOp: 4})
+	if err := h.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err = LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, ok := h.Previous(Result{Day: 1, Part: 1, InputHash: "a"})
+	if !ok || prev.NsPerOp != 3 {
+		t.Errorf("got %+v, want the latest run over the same input", prev)
+	}
+	if _, ok := h.Previous(Result{Day: 2, Part: 1, InputHash: "a"}); ok {
+		t.Error("found a previous result for a day that was never run")
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	if err := SetBenchTime(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	s := solver.Day{Part1: func(r io.Reader) (string, error) {
+		dat, err := io.ReadAll(r)
+		return strings.ToUpper(string(dat)), err
+	}}
+
+	res, err := Measure(s, 1, 1, []byte("abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Iterations == 0 || res.NsPerOp == 0 || res.AllocsPerOp == 0 {
+		t.Errorf("got %+v, want it to have run and allocated", res)
+	}
+
+	if _, err := Measure(s, 1, 2, []byte("abc")); err != solver.ErrNoPart {
+		t.Errorf("got %v for a missing part, want ErrNoPart", err)
+	}
+}
@@ -0,0 +1,114 @@
+package bench
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// RESULTS_ENV overrides where benchmark results are kept
+const RESULTS_ENV = "AOC_BENCH"
+
+const DEFAULT_RESULTS_PATH = "benchmarks.json"
+
+// DEFAULT_THRESHOLD is how much worse (as a fraction) a measurement has to be
+// than the previous run to count as a regression
+const DEFAULT_THRESHOLD = 0.1
+
+// History is every benchmark result recorded so far, saved as JSON
+type History struct {
+	path    string
+	Results []Result
+}
+
+// Regression is a measurement that got worse by more than the threshold
+type Regression struct {
+	Metric   string
+	Previous int64
+	Current  int64
+}
+
+// Change is the relative change from the previous measurement, e.g. 0.25 for
+// 25% worse
+func (r Regression) Change() float64 {
+	return float64(r.Current-r.Previous) / float64(r.Previous)
+}
+
+// ResultsPath returns where results are kept, according to the environment
+func ResultsPath() string {
+	if path := os.Getenv(RESULTS_ENV); path != "" {
+		return path
+	}
+	return DEFAULT_RESULTS_PATH
+}
+
+// LoadHistory reads the results at path. A missing file is an empty history.
+func LoadHistory(path string) (*History, error) {
+	h := &History{path: path, Results: make([]Result, 0)}
+
+	dat, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(dat, &h.Results); err != nil {
+		return nil, fmt.Errorf("reading benchmark results %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// Save writes the history back to the file it was loaded from
+func (h *History) Save() error {
+	dat, err := json.MarshalIndent(h.Results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(h.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(h.path, append(dat, '\n'), 0o644)
+}
+
+// Record adds a result to the history
+func (h *History) Record(res Result) {
+	h.Results = append(h.Results, res)
+}
+
+// Previous returns the most recent result for the same day, part and input,
+// if there is one
+func (h *History) Previous(res Result) (Result, bool) {
+	for i := len(h.Results) - 1; i >= 0; i-- {
+		prev := h.Results[i]
+		if prev.Day == res.Day && prev.Part == res.Part && prev.InputHash == res.InputHash {
+			return prev, true
+		}
+	}
+	return Result{}, false
+}
+
+// Compare returns every measurement of current that is worse than previous by
+// more than threshold
+func Compare(previous Result, current Result, threshold float64) []Regression {
+	metrics := []Regression{
+		{"time/op", previous.NsPerOp, current.NsPerOp},
+		{"allocs/op", previous.AllocsPerOp, current.AllocsPerOp},
+		{"bytes/op", previous.BytesPerOp, current.BytesPerOp},
+		{"peak heap", int64(previous.PeakHeap), int64(current.PeakHeap)},
+	}
+
+	regressions := make([]Regression, 0)
+	for _, m := range metrics {
+		if float64(m.Current) > float64(m.Previous)*(1+threshold) {
+			regressions = append(regressions, m)
+		}
+	}
+	return regressions
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/FaideWW/aoc-2022/bench"
+	"github.com/FaideWW/aoc-2022/days"
+	"github.com/FaideWW/aoc-2022/inputs"
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+func benchCommand(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	part := fs.Int("part", 0, "only benchmark this part (1 or 2)")
+	inputPath := fs.String("input", "", "path to the puzzle input (default: the cached input for the day)")
+	benchTime := fs.Duration("benchtime", time.Second, "how long to run each part for")
+	resultsPath := fs.String("results", bench.ResultsPath(), "file the results are compared against and recorded in")
+	threshold := fs.Float64("threshold", bench.DEFAULT_THRESHOLD, "how much worse than the previous run (as a fraction) counts as a regression")
+	dryRun := fs.Bool("dry-run", false, "compare against the previous run without recording this one")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("expected exactly one day (or \"all\")")
+	}
+	if *part != 0 && *part != 1 && *part != 2 {
+		return fmt.Errorf("invalid part %d", *part)
+	}
+	if err := bench.SetBenchTime(*benchTime); err != nil {
+		return err
+	}
+
+	toRun := make([]int, 0)
+	if positional[0] == "all" {
+		if *inputPath != "" {
+			return errors.New("--input cannot be used when benchmarking all days")
+		}
+		for day := 1; day <= len(days.Solvers); day++ {
+			toRun = append(toRun, day)
+		}
+	} else {
+		day, err := strconv.Atoi(positional[0])
+		if err != nil {
+			return fmt.Errorf("invalid day %q", positional[0])
+		}
+		if _, ok := days.Get(day); !ok {
+			return fmt.Errorf("no solver for day %d", day)
+		}
+		toRun = append(toRun, day)
+	}
+
+	history, err := bench.LoadHistory(*resultsPath)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DAY\tPART\tRUNS\tTIME/OP\tALLOCS/OP\tBYTES/OP\tPEAK HEAP\tSTATUS")
+
+	client := inputs.NewClient()
+	failures := 0
+	regressions := 0
+	for _, day := range toRun {
+		path := *inputPath
+		if path == "" {
+			path, err = client.Get(day)
+			if err != nil {
+				failures++
+				fmt.Fprintf(w, "%d\t\t\t\t\t\t\tFAIL: %s\n", day, err)
+				continue
+			}
+		}
+		dat, err := os.ReadFile(path)
+		if err != nil {
+			failures++
+			fmt.Fprintf(w, "%d\t\t\t\t\t\t\tFAIL: %s\n", day, err)
+			continue
+		}
+
+		s, _ := days.Get(day)
+		for _, p := range partsToRun(*part) {
+			res, err := bench.Measure(s, day, p, dat)
+			if errors.Is(err, solver.ErrNoPart) {
+				continue
+			}
+			if err != nil {
+				failures++
+				fmt.Fprintf(w, "%d\t%d\t\t\t\t\t\tFAIL: %s\n", day, p, err)
+				continue
+			}
+
+			status := "new"
+			if prev, ok := history.Previous(res); ok {
+				status = "ok"
+				if found := bench.Compare(prev, res, *threshold); len(found) > 0 {
+					regressions++
+					status = "REGRESSION: " + formatRegressions(found)
+				}
+			}
+			fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%d\t%s\t%s\t%s\n",
+				day, p, res.Iterations, time.Duration(res.NsPerOp), res.AllocsPerOp,
+				formatBytes(res.BytesPerOp), formatBytes(int64(res.PeakHeap)), status)
+
+			history.Record(res)
+		}
+	}
+	w.Flush()
+
+	if !*dryRun {
+		if err := history.Save(); err != nil {
+			return err
+		}
+	}
+
+	if failures > 0 || regressions > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d failures, %d regressions\n", failures, regressions)
+		return errFailed
+	}
+	return nil
+}
+
+func formatRegressions(regressions []bench.Regression) string {
+	parts := make([]string, len(regressions))
+	for i, r := range regressions {
+		if r.Previous == 0 {
+			parts[i] = fmt.Sprintf("%s was 0", r.Metric)
+			continue
+		}
+		parts[i] = fmt.Sprintf("%s +%.0f%%", r.Metric, r.Change()*100)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatBytes prints a number of bytes with a binary unit, e.g. 1.5MiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	value := float64(n)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB"}
+	i := -1
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f%s", value, suffixes[i])
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/FaideWW/aoc-2022/days"
+	"github.com/FaideWW/aoc-2022/inputs"
+)
+
+func fetchCommand(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	force := fs.Bool("force", false, "download the input again even if it is already cached")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("expected exactly one day (or \"all\")")
+	}
+
+	toFetch := make([]int, 0)
+	if positional[0] == "all" {
+		for day := 1; day <= len(days.Solvers); day++ {
+			toFetch = append(toFetch, day)
+		}
+	} else {
+		day, err := strconv.Atoi(positional[0])
+		if err != nil {
+			return fmt.Errorf("invalid day %q", positional[0])
+		}
+		if _, ok := days.Get(day); !ok {
+			return fmt.Errorf("no solver for day %d", day)
+		}
+		toFetch = append(toFetch, day)
+	}
+
+	client := inputs.NewClient()
+	failures := 0
+	for _, day := range toFetch {
+		if client.IsCached(day) && !*force {
+			fmt.Printf("day %d: already cached at %s\n", day, client.Path(day))
+			continue
+		}
+
+		path, err := client.Download(day)
+		if err != nil {
+			failures++
+			fmt.Fprintf(os.Stderr, "day %d: %s\n", day, err)
+			continue
+		}
+		fmt.Printf("day %d: saved to %s\n", day, path)
+	}
+
+	if failures > 0 {
+		return errFailed
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Command aoc runs the Advent of Code 2022 solvers.
+//
+// Usage:
+//
+//	aoc run <day|all> [--part 1|2] [--input path] [--format text|json] [-v level]
+//	aoc fetch <day|all> [--force]
+//	aoc submit <day> <part> [--input path] [--answer value] [--history path]
+//	aoc bench <day|all> [--part 1|2] [--input path] [--benchtime 1s] [--results path] [--threshold 0.1] [--dry-run]
+//
+// Puzzle inputs are cached under days/<day>/input.txt (or $AOC_INPUT_DIR), and
+// missing inputs are downloaded from adventofcode.com using the session cookie
+// in $AOC_SESSION. Every submitted answer is recorded in submissions.json (or
+// $AOC_HISTORY), and answers already known to be wrong are never resubmitted.
+// Benchmark results are recorded in benchmarks.json (or $AOC_BENCH), and each
+// run is compared against the previous one.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type command struct {
+	name    string
+	usage   string
+	execute func(args []string) error
+}
+
+var commands = []command{
+	{"run", "run <day|all> [--part 1|2] [--input path] [--format text|json] [-v level]", runCommand},
+	{"fetch", "fetch <day|all> [--force]", fetchCommand},
+	{"submit", "submit <day> <part> [--input path] [--answer value] [--history path]", submitCommand},
+	{"bench", "bench <day|all> [--part 1|2] [--input path] [--benchtime 1s] [--results path] [--threshold 0.1] [--dry-run]", benchCommand},
+}
+
+// errFailed is returned by commands that have already reported their failure
+var errFailed = errors.New("failed")
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != os.Args[1] {
+			continue
+		}
+
+		err := cmd.execute(os.Args[2:])
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		if err != nil {
+			if !errors.Is(err, errFailed) {
+				fmt.Fprintf(os.Stderr, "aoc %s: %s\n", cmd.name, err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  aoc %s\n", cmd.usage)
+	}
+}
+
+// parseArgs parses flags that may appear before, between or after the
+// positional arguments, and returns the positional arguments in order
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := make([]string, 0)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// formatAnswer starts multi-line answers (such as images) on their own line
+func formatAnswer(answer string) string {
+	answer = strings.TrimRight(answer, "\n")
+	if strings.Contains(answer, "\n") {
+		return "\n" + answer
+	}
+	return answer
+}
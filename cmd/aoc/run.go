@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/FaideWW/aoc-2022/days"
+	"github.com/FaideWW/aoc-2022/inputs"
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+type result struct {
+	day       int
+	part      int
+	answer    string
+	duration  time.Duration
+	inputHash string
+	err       error
+}
+
+// jsonResult is how a result is written with --format json, one per line
+type jsonResult struct {
+	Day    int    `json:"day"`
+	Part   int    `json:"part,omitempty"`
+	Answer string `json:"answer,omitempty"`
+	// Duration is in nanoseconds
+	Duration  time.Duration `json:"duration"`
+	InputHash string        `json:"input_hash,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+func (res result) toJSON() jsonResult {
+	j := jsonResult{
+		Day:       res.day,
+		Part:      res.part,
+		Answer:    res.answer,
+		Duration:  res.duration,
+		InputHash: res.inputHash,
+	}
+	if res.err != nil {
+		j.Error = res.err.Error()
+	}
+	return j
+}
+
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	part := fs.Int("part", 0, "only solve this part (1 or 2)")
+	inputPath := fs.String("input", "", "path to the puzzle input (defaults to the cached input, downloading it if needed)")
+	format := fs.String("format", "text", "output format: text, or json for one JSON record per part")
+	fs.IntVar(&solver.Verbosity, "v", 0, "diagnostic output written to stderr: 1 for debug, 2 for traces")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("expected exactly one day (or \"all\")")
+	}
+	if *part != 0 && *part != 1 && *part != 2 {
+		return fmt.Errorf("invalid part %d", *part)
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid format %q", *format)
+	}
+
+	if positional[0] == "all" {
+		if *inputPath != "" {
+			return errors.New("--input cannot be used when running all days")
+		}
+		if *format == "json" {
+			return runAllJSON(*part)
+		}
+		return runAll(*part)
+	}
+
+	day, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return fmt.Errorf("invalid day %q", positional[0])
+	}
+	if _, ok := days.Get(day); !ok {
+		return fmt.Errorf("no solver for day %d", day)
+	}
+
+	path := *inputPath
+	if path == "" {
+		path, err = inputs.NewClient().Get(day)
+		if err != nil {
+			return err
+		}
+	}
+
+	results, err := runDay(day, path, *part)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		return writeJSON(results)
+	}
+
+	for _, res := range results {
+		if errors.Is(res.err, solver.ErrNoPart) {
+			continue
+		}
+		if res.err != nil {
+			return fmt.Errorf("day %d part %d: %w", res.day, res.part, res.err)
+		}
+		fmt.Printf("day %d part %d: %s\n", res.day, res.part, formatAnswer(res.answer))
+	}
+	return nil
+}
+
+// partsToRun expands the --part flag, where 0 means both parts
+func partsToRun(part int) []int {
+	if part == 0 {
+		return []int{1, 2}
+	}
+	return []int{part}
+}
+
+// runDay solves the requested parts of a day's puzzle with the input at path.
+// The returned error is only set if the input couldn't be read; errors from
+// the solver are reported in each part's result.
+func runDay(day int, path string, part int) ([]result, error) {
+	s, _ := days.Get(day)
+
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(dat)
+	inputHash := hex.EncodeToString(hash[:])
+
+	results := make([]result, 0, 2)
+	for _, p := range partsToRun(part) {
+		res := result{day: day, part: p, inputHash: inputHash}
+		start := time.Now()
+		res.answer, res.err = s.Solve(p, bytes.NewReader(dat))
+		res.duration = time.Since(start)
+		// Point problems with the input at the file they came from
+		var parseErr *parse.Error
+		if errors.As(res.err, &parseErr) {
+			parseErr.Path = path
+		}
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// runAll solves every day with its cached input and prints a table of the
+// results. Answers that span multiple lines are printed in full below the
+// table.
+func runAll(part int) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DAY\tPART\tANSWER\tTIME\tSTATUS")
+
+	client := inputs.NewClient()
+	failures := 0
+	multiline := make([]string, 0)
+	for day := 1; day <= len(days.Solvers); day++ {
+		path, err := client.Get(day)
+		if err != nil {
+			failures++
+			fmt.Fprintf(w, "%d\t\t\t\tFAIL: %s\n", day, err)
+			continue
+		}
+
+		results, err := runDay(day, path, part)
+		if err != nil {
+			failures++
+			fmt.Fprintf(w, "%d\t\t\t\tFAIL: %s\n", day, err)
+			continue
+		}
+
+		for _, res := range results {
+			duration := res.duration.Round(time.Millisecond)
+			switch {
+			case errors.Is(res.err, solver.ErrNoPart):
+				fmt.Fprintf(w, "%d\t%d\t-\t\tskipped\n", res.day, res.part)
+			case res.err != nil:
+				failures++
+				fmt.Fprintf(w, "%d\t%d\t\t%s\tFAIL: %s\n", res.day, res.part, duration, res.err)
+			case strings.Contains(strings.TrimSpace(res.answer), "\n"):
+				multiline = append(multiline, fmt.Sprintf("day %d part %d:\n%s", res.day, res.part, res.answer))
+				fmt.Fprintf(w, "%d\t%d\t(see below)\t%s\tok\n", res.day, res.part, duration)
+			default:
+				fmt.Fprintf(w, "%d\t%d\t%s\t%s\tok\n", res.day, res.part, res.answer, duration)
+			}
+		}
+	}
+	w.Flush()
+
+	for _, m := range multiline {
+		fmt.Printf("\n%s", m)
+	}
+
+	if failures > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d failures\n", failures)
+		return errFailed
+	}
+	return nil
+}
+
+// runAllJSON solves every day with its cached input and writes each result as
+// a line of JSON
+func runAllJSON(part int) error {
+	client := inputs.NewClient()
+	failed := false
+	for day := 1; day <= len(days.Solvers); day++ {
+		path, err := client.Get(day)
+		var results []result
+		if err == nil {
+			results, err = runDay(day, path, part)
+		}
+		if err != nil {
+			// the input couldn't be found, so there are no parts to report
+			results = []result{{day: day, err: err}}
+		}
+
+		if err := writeJSON(results); err != nil {
+			failed = true
+		}
+	}
+
+	if failed {
+		return errFailed
+	}
+	return nil
+}
+
+// writeJSON writes each result as a line of JSON, leaving out parts the day
+// doesn't have. It returns errFailed if any of the results is a failure.
+func writeJSON(results []result) error {
+	enc := json.NewEncoder(os.Stdout)
+	failed := false
+	for _, res := range results {
+		if errors.Is(res.err, solver.ErrNoPart) {
+			continue
+		}
+		if res.err != nil {
+			failed = true
+		}
+		if err := enc.Encode(res.toJSON()); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return errFailed
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/days"
+	"github.com/FaideWW/aoc-2022/inputs"
+	"github.com/FaideWW/aoc-2022/submit"
+)
+
+func submitCommand(args []string) error {
+	fs := flag.NewFlagSet("submit", flag.ContinueOnError)
+	inputPath := fs.String("input", "", "path to the puzzle input (defaults to the cached input, downloading it if needed)")
+	answer := fs.String("answer", "", "submit this answer instead of solving the puzzle")
+	historyPath := fs.String("history", submit.HistoryPath(), "file recording every submitted answer")
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return errors.New("expected a day and a part")
+	}
+
+	day, err := strconv.Atoi(positional[0])
+	if err != nil {
+		return fmt.Errorf("invalid day %q", positional[0])
+	}
+	if _, ok := days.Get(day); !ok {
+		return fmt.Errorf("no solver for day %d", day)
+	}
+	part, err := strconv.Atoi(positional[1])
+	if err != nil || (part != 1 && part != 2) {
+		return fmt.Errorf("invalid part %q", positional[1])
+	}
+
+	if *answer == "" {
+		path := *inputPath
+		if path == "" {
+			path, err = inputs.NewClient().Get(day)
+			if err != nil {
+				return err
+			}
+		}
+
+		results, err := runDay(day, path, part)
+		if err != nil {
+			return err
+		}
+		if results[0].err != nil {
+			return fmt.Errorf("day %d part %d: %w", day, part, results[0].err)
+		}
+		*answer = results[0].answer
+	}
+
+	*answer = strings.TrimSpace(*answer)
+	if strings.Contains(*answer, "\n") {
+		return errors.New("the answer spans multiple lines; read it and pass it with --answer")
+	}
+
+	history, err := submit.LoadHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	if err := history.Check(day, part, *answer); err != nil {
+		return fmt.Errorf("not submitting %s: %w", *answer, err)
+	}
+
+	fmt.Printf("submitting day %d part %d: %s\n", day, part, *answer)
+	res, err := submit.NewClient().Submit(day, part, *answer)
+	if err != nil {
+		return err
+	}
+
+	// an answer the site didn't check tells us nothing worth remembering
+	if res.Outcome != submit.WAIT && res.Outcome != submit.UNKNOWN {
+		history.Record(day, part, *answer, res)
+		if err := history.Save(); err != nil {
+			return err
+		}
+	}
+
+	switch res.Outcome {
+	case submit.CORRECT:
+		{
+			fmt.Println("correct!")
+			return nil
+		}
+	case submit.WAIT:
+		{
+			fmt.Fprintf(os.Stderr, "answered too recently; try again in %s\n", res.Wait)
+		}
+	case submit.UNKNOWN:
+		{
+			fmt.Fprintf(os.Stderr, "unrecognised response: %s\n", res.Message)
+		}
+	default:
+		{
+			fmt.Fprintf(os.Stderr, "%s\n", res.Outcome)
+			if res.Wait > 0 {
+				fmt.Fprintf(os.Stderr, "the next attempt can be made in %s\n", res.Wait)
+			}
+		}
+	}
+	return errFailed
+}
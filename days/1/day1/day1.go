@@ -0,0 +1,225 @@
+package day1
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+// The number of buckets in the histogram printed at debug verbosity
+const HISTOGRAM_BUCKETS = 10
+
+// Elf is the total carried by one elf (or, more generally, the sum of one
+// group of lines). Index counts the elves from 1, in the order they appear.
+type Elf struct {
+	Index    int
+	Calories int
+}
+
+// Stats summarises every elf read from an input. Only each elf's total is
+// kept, never the lines themselves.
+type Stats struct {
+	Count int
+	Total int
+	// The elves carrying the most calories, most first
+	Top    []Elf
+	Min    Elf
+	Max    Elf
+	totals []int
+}
+
+// Bucket counts the elves carrying between From and To calories (inclusive)
+type Bucket struct {
+	From  int
+	To    int
+	Count int
+}
+
+// Part1 returns the largest calorie count carried by a single elf
+func Part1(r io.Reader) (string, error) {
+	top, err := TopElves(r, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(top) == 0 {
+		return "", errors.New("no elves in the input")
+	}
+	return fmt.Sprint(top[0].Calories), nil
+}
+
+// Part2 returns the sum of the calories carried by the top three elves
+func Part2(r io.Reader) (string, error) {
+	var top []Elf
+	var err error
+	if solver.Verbosity >= solver.DEBUG {
+		// The statistics need every elf's total, so they are only worked out
+		// when they are going to be printed
+		var stats Stats
+		stats, err = Summarize(r, 3)
+		top = stats.Top
+		solver.Debugf(solver.DEBUG, "%s", stats)
+	} else {
+		top, err = TopElves(r, 3)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(top) < 3 {
+		return "", fmt.Errorf("expected at least 3 elves, got %d", len(top))
+	}
+
+	sum := 0
+	for _, elf := range top {
+		sum += elf.Calories
+	}
+	return fmt.Sprint(sum), nil
+}
+
+// ReadElves streams the input one line at a time, calling fn with each elf's
+// total as soon as the blank line (or end of input) after it is read. Runs of
+// blank lines count as a single separator.
+func ReadElves(r io.Reader, fn func(Elf) error) error {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	elf := Elf{Index: 1}
+	items := 0
+	for scanner.Scan() {
+		line++
+		field := parse.Field{Text: scanner.Text(), Line: line, Col: 1}.TrimSpace()
+		if len(field.Text) == 0 {
+			if items > 0 {
+				if err := fn(elf); err != nil {
+					return err
+				}
+				elf = Elf{Index: elf.Index + 1}
+				items = 0
+			}
+			continue
+		}
+
+		calories, err := field.Int()
+		if err != nil {
+			return err
+		}
+		elf.Calories += calories
+		items++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if items > 0 {
+		return fn(elf)
+	}
+	return nil
+}
+
+// TopElves returns the k elves carrying the most calories, most first
+func TopElves(r io.Reader, k int) ([]Elf, error) {
+	top := NewTopK[Elf](k)
+	err := ReadElves(r, func(elf Elf) error {
+		top.Push(elf, elf.Calories)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	elves, _ := top.Values()
+	return elves, nil
+}
+
+// Summarize reads every elf, keeping the k elves carrying the most calories
+// and enough to work out the mean, median and histogram of the totals. Unlike
+// TopElves, it keeps every elf's total (though not the lines making it up), so
+// its memory grows with the number of elves.
+func Summarize(r io.Reader, k int) (Stats, error) {
+	stats := Stats{totals: make([]int, 0)}
+	top := NewTopK[Elf](k)
+	err := ReadElves(r, func(elf Elf) error {
+		if stats.Count == 0 || elf.Calories < stats.Min.Calories {
+			stats.Min = elf
+		}
+		if stats.Count == 0 || elf.Calories > stats.Max.Calories {
+			stats.Max = elf
+		}
+		stats.Count++
+		stats.Total += elf.Calories
+		stats.totals = append(stats.totals, elf.Calories)
+		top.Push(elf, elf.Calories)
+		return nil
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+
+	stats.Top, _ = top.Values()
+	sort.Ints(stats.totals)
+	return stats, nil
+}
+
+func (s Stats) Mean() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Total) / float64(s.Count)
+}
+
+func (s Stats) Median() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	mid := s.Count / 2
+	if s.Count%2 == 1 {
+		return float64(s.totals[mid])
+	}
+	return float64(s.totals[mid-1]+s.totals[mid]) / 2
+}
+
+// Histogram splits the range of totals into n equal buckets and counts the
+// elves in each
+func (s Stats) Histogram(n int) []Bucket {
+	if s.Count == 0 || n <= 0 {
+		return []Bucket{}
+	}
+
+	width := (s.Max.Calories - s.Min.Calories + n) / n
+	buckets := make([]Bucket, n)
+	for i := range buckets {
+		buckets[i].From = s.Min.Calories + i*width
+		buckets[i].To = buckets[i].From + width - 1
+	}
+	for _, total := range s.totals {
+		buckets[(total-s.Min.Calories)/width].Count++
+	}
+	return buckets
+}
+
+func (s Stats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d elves carrying %d calories\n", s.Count, s.Total)
+	fmt.Fprintf(&b, "mean %.1f, median %.1f\n", s.Mean(), s.Median())
+	fmt.Fprintf(&b, "least: elf %d with %d\n", s.Min.Index, s.Min.Calories)
+	for i, elf := range s.Top {
+		fmt.Fprintf(&b, "#%d: elf %d with %d\n", i+1, elf.Index, elf.Calories)
+	}
+
+	buckets := s.Histogram(HISTOGRAM_BUCKETS)
+	most := 0
+	for _, bucket := range buckets {
+		if bucket.Count > most {
+			most = bucket.Count
+		}
+	}
+	for _, bucket := range buckets {
+		bar := strings.Repeat("#", bucket.Count*40/most)
+		fmt.Fprintf(&b, "%6d-%-6d %4d %s\n", bucket.From, bucket.To, bucket.Count, bar)
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package day1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const example = "1000\n2000\n3000\n\n4000\n\n5000\n6000\n\n7000\n8000\n9000\n\n10000\n"
+
+func summarize(t *testing.T, input string, k int) Stats {
+	t.Helper()
+	stats, err := Summarize(strings.NewReader(input), k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stats
+}
+
+func TestSummarize(t *testing.T) {
+	stats := summarize(t, example, 2)
+	if stats.Count != 5 || stats.Total != 55000 {
+		t.Errorf("got %d elves carrying %d, want 5 carrying 55000", stats.Count, stats.Total)
+	}
+	if want := []Elf{{4, 24000}, {3, 11000}}; !reflect.DeepEqual(stats.Top, want) {
+		t.Errorf("got top elves %v, want %v", stats.Top, want)
+	}
+	if stats.Min != (Elf{2, 4000}) || stats.Max != (Elf{4, 24000}) {
+		t.Errorf("got least %v and most %v", stats.Min, stats.Max)
+	}
+	if got := stats.Mean(); got != 11000 {
+		t.Errorf("got mean %v, want 11000", got)
+	}
+	if got := stats.Median(); got != 10000 {
+		t.Errorf("got median %v, want 10000", got)
+	}
+
+	// With an even number of elves, the median is halfway between the middle
+	// two
+	even := summarize(t, example[:strings.LastIndex(example, "\n\n")], 1)
+	if got := even.Median(); got != 8500 {
+		t.Errorf("got median %v, want 8500", got)
+	}
+
+	empty := summarize(t, "\n\n", 3)
+	if empty.Count != 0 || empty.Mean() != 0 || empty.Median() != 0 || len(empty.Histogram(3)) != 0 {
+		t.Errorf("got %+v for no elves", empty)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	// Totals are 4000, 6000, 10000, 11000 and 24000
+	want := []Bucket{
+		{From: 4000, To: 10666, Count: 3},
+		{From: 10667, To: 17333, Count: 1},
+		{From: 17334, To: 24000, Count: 1},
+	}
+	if got := summarize(t, example, 1).Histogram(3); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Every elf lands in the first bucket when they all carry the same
+	same := summarize(t, "5\n\n5\n\n5\n", 1).Histogram(2)
+	if same[0].Count != 3 || same[1].Count != 0 {
+		t.Errorf("got %v, want every elf in the first bucket", same)
+	}
+}
+
+func TestTopElvesStreams(t *testing.T) {
+	top, err := TopElves(strings.NewReader(example), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Elf{{4, 24000}, {3, 11000}, {5, 10000}}; !reflect.DeepEqual(top, want) {
+		t.Errorf("got %v, want %v", top, want)
+	}
+}
@@ -0,0 +1,44 @@
+package day1
+
+import "github.com/FaideWW/aoc-2022/search"
+
+// TopK keeps the k values with the highest scores seen so far, using a
+// min-heap of at most k values so that memory stays bounded however many
+// values are pushed
+type TopK[T any] struct {
+	k     int
+	queue *search.PriorityQueue[T]
+}
+
+func NewTopK[T any](k int) *TopK[T] {
+	return &TopK[T]{k: k, queue: search.NewPriorityQueue[T]()}
+}
+
+func (t *TopK[T]) Len() int { return t.queue.Len() }
+
+// Push offers a value. It is kept if it is among the k highest scores so far;
+// which of several values tied for the last place is kept is unspecified.
+func (t *TopK[T]) Push(value T, score int) {
+	if t.k <= 0 {
+		return
+	}
+	if t.queue.Len() < t.k {
+		t.queue.Push(value, score)
+		return
+	}
+	if _, lowest := t.queue.Peek(); score > lowest {
+		t.queue.Pop()
+		t.queue.Push(value, score)
+	}
+}
+
+// Values returns the kept values and their scores, highest score first. The
+// TopK is emptied in the process.
+func (t *TopK[T]) Values() ([]T, []int) {
+	values := make([]T, t.queue.Len())
+	scores := make([]int, t.queue.Len())
+	for i := len(values) - 1; i >= 0; i-- {
+		values[i], scores[i] = t.queue.Pop()
+	}
+	return values, scores
+}
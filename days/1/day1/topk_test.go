@@ -0,0 +1,28 @@
+package day1
+
+import "testing"
+
+func TestTopK(t *testing.T) {
+	top := NewTopK[string](3)
+	scores := map[string]int{"a": 5, "b": 1, "c": 9, "d": 5, "e": 7, "f": 2}
+	for _, v := range []string{"a", "b", "c", "d", "e", "f"} {
+		top.Push(v, scores[v])
+	}
+	if top.Len() != 3 {
+		t.Fatalf("kept %d values, want 3", top.Len())
+	}
+
+	values, got := top.Values()
+	want := []int{9, 7, 5}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got scores %v, want %v", got, want)
+		}
+	}
+	if values[0] != "c" || values[1] != "e" {
+		t.Errorf("got %v, want c and e first", values)
+	}
+	if top.Len() != 0 {
+		t.Errorf("Values left %d values behind", top.Len())
+	}
+}
@@ -0,0 +1,321 @@
+package day10
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+// Operand is the kind of value an instruction takes
+type Operand int
+
+const (
+	// A number written in the program
+	VALUE Operand = iota
+	// The name of a register
+	REGISTER
+)
+
+// Op is an instruction the CPU understands
+type Op struct {
+	Name string
+	// The number of cycles the instruction takes. Its effect only applies at
+	// the end of its last cycle, so registers keep their old values until then.
+	Cycles   int
+	Operands []Operand
+	// Exec applies the instruction to the CPU. Register operands are passed as
+	// the register's index.
+	Exec func(c *CPU, args []int)
+}
+
+// Register is a named register and the value it starts with
+type Register struct {
+	Name    string
+	Initial int
+}
+
+// InstructionSet describes a CPU: its registers and the instructions it can
+// run
+type InstructionSet struct {
+	registers []Register
+	ops       map[string]Op
+}
+
+// Instruction is an Op along with its operands, as written in a program
+type Instruction struct {
+	op   Op
+	args []int
+	// The instruction as written in the program
+	text string
+	line int
+}
+
+// CPU runs a program one cycle at a time, remembering the registers during
+// every cycle
+type CPU struct {
+	isa       *InstructionSet
+	program   []Instruction
+	registers []int
+	// The index of the instruction being executed, or the next one to start
+	pc int
+	// The cycles left before the current instruction completes, or 0 if the
+	// next cycle starts a new instruction
+	busy int
+	// The number of cycles that have completed
+	cycle int
+	// The registers during each cycle, starting with cycle 1
+	history [][]int
+}
+
+// NewInstructionSet returns an instruction set with the given registers and
+// no instructions
+func NewInstructionSet(registers ...Register) *InstructionSet {
+	return &InstructionSet{registers: registers, ops: make(map[string]Op)}
+}
+
+// DeviceInstructionSet returns the instruction set of the handheld device
+// from the puzzle, with a single register X starting at 1
+func DeviceInstructionSet() *InstructionSet {
+	isa := NewInstructionSet(Register{Name: "x", Initial: 1})
+	isa.MustRegister(Op{
+		Name:   "noop",
+		Cycles: 1,
+		Exec:   func(c *CPU, args []int) {},
+	})
+	isa.MustRegister(Op{
+		Name:     "addx",
+		Cycles:   2,
+		Operands: []Operand{VALUE},
+		Exec: func(c *CPU, args []int) {
+			c.registers[0] += args[0]
+		},
+	})
+	return isa
+}
+
+// Register adds an instruction to the set
+func (s *InstructionSet) Register(op Op) error {
+	if _, ok := s.ops[op.Name]; ok {
+		return fmt.Errorf("instruction %q is already registered", op.Name)
+	}
+	if op.Cycles < 1 {
+		return fmt.Errorf("instruction %q must take at least one cycle", op.Name)
+	}
+	if op.Exec == nil {
+		return fmt.Errorf("instruction %q does nothing", op.Name)
+	}
+	s.ops[op.Name] = op
+	return nil
+}
+
+// MustRegister is like Register, but panics if the instruction can't be
+// added. It is meant for building instruction sets up front.
+func (s *InstructionSet) MustRegister(op Op) {
+	if err := s.Register(op); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterIndex returns the index of the named register
+func (s *InstructionSet) RegisterIndex(name string) (int, bool) {
+	for i, r := range s.registers {
+		if r.Name == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// RegisterNames returns the names of the registers, in order
+func (s *InstructionSet) RegisterNames() []string {
+	names := make([]string, len(s.registers))
+	for i, r := range s.registers {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// Parse reads a program, one instruction per line
+func (s *InstructionSet) Parse(input string) ([]Instruction, error) {
+	lines := parse.Lines(input)
+	program := make([]Instruction, 0, len(lines))
+	for _, line := range lines {
+		if len(strings.TrimSpace(line.Text)) == 0 {
+			continue
+		}
+		instruction, err := s.parseInstruction(line)
+		if err != nil {
+			return nil, err
+		}
+		program = append(program, instruction)
+	}
+	return program, nil
+}
+
+func (s *InstructionSet) parseInstruction(line parse.Field) (Instruction, error) {
+	parts := line.Fields()
+	op, ok := s.ops[parts[0].Text]
+	if !ok {
+		return Instruction{}, parts[0].Errorf("unrecognized instruction %q", parts[0].Text)
+	}
+	if len(parts)-1 != len(op.Operands) {
+		return Instruction{}, line.Errorf("%s takes %d operand(s), got %d", op.Name, len(op.Operands), len(parts)-1)
+	}
+
+	args := make([]int, len(op.Operands))
+	for i, kind := range op.Operands {
+		field := parts[i+1]
+		switch kind {
+		case VALUE:
+			{
+				value, err := field.Int()
+				if err != nil {
+					return Instruction{}, err
+				}
+				args[i] = value
+			}
+		case REGISTER:
+			{
+				index, ok := s.RegisterIndex(field.Text)
+				if !ok {
+					return Instruction{}, field.Errorf("no register %q", field.Text)
+				}
+				args[i] = index
+			}
+		}
+	}
+	return Instruction{op: op, args: args, text: strings.Join(strings.Fields(line.Text), " "), line: line.Line}, nil
+}
+
+func (i Instruction) String() string {
+	return i.text
+}
+
+// NewCPU returns a CPU ready to run the program from its first cycle
+func NewCPU(isa *InstructionSet, program []Instruction) *CPU {
+	c := &CPU{
+		isa:       isa,
+		program:   program,
+		registers: make([]int, len(isa.registers)),
+		history:   make([][]int, 0),
+	}
+	for i, r := range isa.registers {
+		c.registers[i] = r.Initial
+	}
+	return c
+}
+
+// Halted returns whether every instruction has completed
+func (c *CPU) Halted() bool {
+	return c.pc >= len(c.program) && c.busy == 0
+}
+
+// Tick runs a single cycle, returning false if the program had already
+// finished
+func (c *CPU) Tick() bool {
+	if c.Halted() {
+		return false
+	}
+	if c.busy == 0 {
+		c.busy = c.program[c.pc].op.Cycles
+	}
+
+	c.cycle++
+	c.history = append(c.history, append([]int{}, c.registers...))
+
+	c.busy--
+	if c.busy == 0 {
+		instruction := c.program[c.pc]
+		instruction.op.Exec(c, instruction.args)
+		c.pc++
+	}
+	return true
+}
+
+// Run runs the program until it finishes
+func (c *CPU) Run() {
+	for c.Tick() {
+	}
+}
+
+// Cycle returns the number of cycles that have completed
+func (c *CPU) Cycle() int {
+	return c.cycle
+}
+
+// Current returns the instruction being executed, or the next one to start,
+// along with how many of its cycles have completed. It returns false once the
+// program has finished.
+func (c *CPU) Current() (Instruction, int, bool) {
+	if c.Halted() {
+		return Instruction{}, 0, false
+	}
+	instruction := c.program[c.pc]
+	done := 0
+	if c.busy > 0 {
+		done = instruction.op.Cycles - c.busy
+	}
+	return instruction, done, true
+}
+
+// Get returns the current value of a register
+func (c *CPU) Get(register int) int {
+	return c.registers[register]
+}
+
+// Set changes the value of a register, for instructions to use
+func (c *CPU) Set(register int, value int) {
+	c.registers[register] = value
+}
+
+// During returns the registers during the given cycle, counting from 1. The
+// registers during the next cycle are known too, since instructions only
+// change them at the end of a cycle.
+func (c *CPU) During(cycle int) ([]int, bool) {
+	switch {
+	case cycle < 1 || cycle > c.cycle+1:
+		{
+			return nil, false
+		}
+	case cycle == c.cycle+1:
+		{
+			return append([]int{}, c.registers...), true
+		}
+	default:
+		{
+			return c.history[cycle-1], true
+		}
+	}
+}
+
+// Value returns the named register's value during the given cycle
+func (c *CPU) Value(name string, cycle int) (int, error) {
+	index, ok := c.isa.RegisterIndex(name)
+	if !ok {
+		return 0, fmt.Errorf("no register %q", name)
+	}
+	registers, ok := c.During(cycle)
+	if !ok {
+		return 0, fmt.Errorf("cycle %d hasn't happened yet", cycle)
+	}
+	return registers[index], nil
+}
+
+// String describes the registers during the next cycle, and the instruction
+// being executed
+func (c *CPU) String() string {
+	var b strings.Builder
+	if c.Halted() {
+		fmt.Fprintf(&b, "halted after cycle %d:", c.cycle)
+	} else {
+		fmt.Fprintf(&b, "during cycle %d:", c.cycle+1)
+	}
+	for i, name := range c.isa.RegisterNames() {
+		fmt.Fprintf(&b, " %s=%d", name, c.registers[i])
+	}
+	if instruction, done, ok := c.Current(); ok {
+		fmt.Fprintf(&b, ", line %d: %s (%d/%d cycles done)", instruction.line, instruction, done, instruction.op.Cycles)
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package day10
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+	"github.com/FaideWW/aoc-2022/ocr"
+)
+
+const CRT_WIDTH = 40
+const CRT_HEIGHT = 6
+
+// CRT draws one pixel per cycle, left to right and top to bottom, lighting it
+// if the sprite (three pixels wide, centred on the value of a register)
+// covers it
+type CRT struct {
+	Width  int
+	Height int
+	// The register holding the sprite's position
+	Sprite string
+}
+
+// DeviceCRT is the screen of the handheld device from the puzzle
+var DeviceCRT = CRT{Width: CRT_WIDTH, Height: CRT_HEIGHT, Sprite: "x"}
+
+// The colours of lit and dark pixels in images of the screen
+var (
+	litColour  = color.Gray{Y: 0xff}
+	darkColour = color.Gray{Y: 0x00}
+)
+
+// Draw returns the pixels drawn so far, along with how many there are. Pixels
+// are drawn during each cycle, so the pixel for the cycle the CPU is about to
+// run is already known.
+func (crt CRT) Draw(c *CPU) (*grid.Dense[bool], int, error) {
+	if crt.Width < 1 || crt.Height < 1 {
+		return nil, 0, fmt.Errorf("invalid screen size %dx%d", crt.Width, crt.Height)
+	}
+	sprite, ok := c.isa.RegisterIndex(crt.Sprite)
+	if !ok {
+		return nil, 0, fmt.Errorf("no register %q for the sprite", crt.Sprite)
+	}
+
+	pixels := grid.NewDense[bool](crt.Width, crt.Height)
+	drawn := 0
+	for ; drawn < crt.Width*crt.Height; drawn++ {
+		registers, ok := c.During(drawn + 1)
+		if !ok {
+			break
+		}
+		p := grid.Vec2{X: drawn % crt.Width, Y: drawn / crt.Width}
+		spritePosition := registers[sprite]
+		if p.X-spritePosition < 2 && spritePosition-p.X < 2 {
+			pixels.Set(p, true)
+		}
+	}
+	return pixels, drawn, nil
+}
+
+// Render draws the whole screen as # and . characters, as the puzzle does.
+// The program has to run for long enough to draw every pixel.
+func (crt CRT) Render(c *CPU) (string, error) {
+	pixels, err := crt.drawAll(c)
+	if err != nil {
+		return "", err
+	}
+	return renderPixels(pixels), nil
+}
+
+// Read returns the letters shown on the whole screen
+func (crt CRT) Read(c *CPU) (string, error) {
+	pixels, err := crt.drawAll(c)
+	if err != nil {
+		return "", err
+	}
+	return ocr.Read(pixels)
+}
+
+// drawAll is like Draw, but requires the program to draw every pixel
+func (crt CRT) drawAll(c *CPU) (*grid.Dense[bool], error) {
+	pixels, drawn, err := crt.Draw(c)
+	if err != nil {
+		return nil, err
+	}
+	if drawn < crt.Width*crt.Height {
+		return nil, fmt.Errorf("the program ended after drawing %d of the %d pixels", drawn, crt.Width*crt.Height)
+	}
+	return pixels, nil
+}
+
+func renderPixels(pixels *grid.Dense[bool]) string {
+	var b strings.Builder
+	for y := 0; y < pixels.Height(); y++ {
+		for x := 0; x < pixels.Width(); x++ {
+			if pixels.At(grid.Vec2{X: x, Y: y}) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Image draws the pixels drawn so far as an image, with each pixel scale
+// pixels wide
+func (crt CRT) Image(c *CPU, scale int) (image.Image, error) {
+	if scale < 1 {
+		return nil, fmt.Errorf("invalid scale %d", scale)
+	}
+	pixels, _, err := crt.Draw(c)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewPaletted(image.Rect(0, 0, crt.Width*scale, crt.Height*scale), color.Palette{darkColour, litColour})
+	for y := 0; y < crt.Height*scale; y++ {
+		for x := 0; x < crt.Width*scale; x++ {
+			if pixels.At(grid.Vec2{X: x / scale, Y: y / scale}) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return img, nil
+}
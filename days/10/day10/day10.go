@@ -0,0 +1,74 @@
+package day10
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The cycles during which the signal strength is sampled in part 1
+var SAMPLE_CYCLES = []int{20, 60, 100, 140, 180, 220}
+
+// Part1 returns the sum of the signal strengths at the sampled cycles
+func Part1(r io.Reader) (string, error) {
+	cpu, err := run(r)
+	if err != nil {
+		return "", err
+	}
+
+	sum := 0
+	for _, t := range SAMPLE_CYCLES {
+		strength, err := getSignalStrength(cpu, t)
+		if err != nil {
+			return "", err
+		}
+		sum += strength
+	}
+
+	return fmt.Sprint(sum), nil
+}
+
+// Part2 returns the letters drawn on the CRT
+func Part2(r io.Reader) (string, error) {
+	cpu, err := run(r)
+	if err != nil {
+		return "", err
+	}
+	return DeviceCRT.Read(cpu)
+}
+
+func run(r io.Reader) (*CPU, error) {
+	cpu, err := Load(r, DeviceInstructionSet())
+	if err != nil {
+		return nil, err
+	}
+	cpu.Run()
+	return cpu, nil
+}
+
+// Load reads a program written for the instruction set, returning a CPU ready
+// to run it
+func Load(r io.Reader, isa *InstructionSet) (*CPU, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return nil, err
+	}
+	program, err := isa.Parse(strings.TrimSpace(input))
+	if err != nil {
+		return nil, err
+	}
+	return NewCPU(isa, program), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func getSignalStrength(c *CPU, t int) (int, error) {
+	x, err := c.Value("x", t)
+	if err != nil {
+		return 0, fmt.Errorf("the program ended before cycle %d", t)
+	}
+	return x * t, nil
+}
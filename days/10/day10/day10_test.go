@@ -0,0 +1,240 @@
+package day10
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/FaideWW/aoc-2022/ocr"
+)
+
+// twoRegisters is a small instruction set with a slow instruction working on
+// either of two registers
+func twoRegisters() *InstructionSet {
+	isa := NewInstructionSet(Register{Name: "a", Initial: 0}, Register{Name: "b", Initial: 10})
+	isa.MustRegister(Op{
+		Name:     "add",
+		Cycles:   3,
+		Operands: []Operand{REGISTER, VALUE},
+		Exec: func(c *CPU, args []int) {
+			c.Set(args[0], c.Get(args[0])+args[1])
+		},
+	})
+	isa.MustRegister(Op{
+		Name:     "mov",
+		Cycles:   1,
+		Operands: []Operand{REGISTER, REGISTER},
+		Exec: func(c *CPU, args []int) {
+			c.Set(args[0], c.Get(args[1]))
+		},
+	})
+	return isa
+}
+
+func TestCustomInstructionSet(t *testing.T) {
+	cpu, err := Load(strings.NewReader("add a 5\nmov b a\nadd b -1\n"), twoRegisters())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu.Run()
+
+	if cpu.Cycle() != 7 {
+		t.Errorf("took %d cycles, want 7", cpu.Cycle())
+	}
+	// add only takes effect at the end of its third cycle
+	tests := []struct {
+		register string
+		cycle    int
+		want     int
+	}{
+		{"a", 3, 0}, {"a", 4, 5}, {"b", 4, 10}, {"b", 5, 5}, {"b", 7, 5}, {"b", 8, 4},
+	}
+	for _, test := range tests {
+		got, err := cpu.Value(test.register, test.cycle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%s during cycle %d is %d, want %d", test.register, test.cycle, got, test.want)
+		}
+	}
+
+	for _, program := range []string{"add c 1", "add a", "sub a 1", "mov a 1"} {
+		if _, err := Load(strings.NewReader(program), twoRegisters()); err == nil {
+			t.Errorf("%q: expected an error", program)
+		}
+	}
+	if err := twoRegisters().Register(Op{Name: "nop", Exec: func(c *CPU, args []int) {}}); err == nil {
+		t.Error("expected an instruction taking no cycles to be rejected")
+	}
+}
+
+func loadTest(t *testing.T) *CPU {
+	t.Helper()
+	f, err := os.Open("../test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cpu, err := Load(f, DeviceInstructionSet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cpu
+}
+
+func TestBreakpoints(t *testing.T) {
+	cpu := loadTest(t)
+	d := NewDebugger(cpu, DeviceCRT)
+	for _, s := range []string{"60", "x=21"} {
+		b, err := ParseBreakpoint(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.AddBreakpoint(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// x becomes 21 at the end of cycle 18
+	if hit := d.Continue(); hit != 2 || cpu.Cycle() != 18 {
+		t.Fatalf("stopped at breakpoint %d after cycle %d, want 2 after cycle 18", hit, cpu.Cycle())
+	}
+	if hit := d.Continue(); hit != 1 || cpu.Cycle() != 59 {
+		t.Fatalf("stopped at breakpoint %d after cycle %d, want 1 after cycle 59", hit, cpu.Cycle())
+	}
+	if x, _ := cpu.Value("x", 60); x != 19 {
+		t.Errorf("x during cycle 60 is %d, want 19", x)
+	}
+	if err := d.DeleteBreakpoint(2); err != nil {
+		t.Fatal(err)
+	}
+	if hit := d.Continue(); hit != 0 || !cpu.Halted() {
+		t.Errorf("stopped at breakpoint %d, want the program to finish", hit)
+	}
+
+	if _, err := d.AddBreakpoint(Breakpoint{Register: "y", Value: 1}); err == nil {
+		t.Error("expected a breakpoint on a missing register to be rejected")
+	}
+}
+
+func TestEarlyBreakpoints(t *testing.T) {
+	// Continuing always runs the next cycle, so it can't stop before it
+	for _, s := range []string{"1", "0", "-3", "x", "=1"} {
+		if b, err := ParseBreakpoint(s); err == nil {
+			t.Errorf("%q: expected an error, got %s", s, b)
+		}
+	}
+
+	cpu := loadTest(t)
+	d := NewDebugger(cpu, DeviceCRT)
+	b, err := ParseBreakpoint("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AddBreakpoint(b); err != nil {
+		t.Fatal(err)
+	}
+	if hit := d.Continue(); hit != 1 || cpu.Cycle() != 1 {
+		t.Fatalf("stopped at breakpoint %d after cycle %d, want 1 after cycle 1", hit, cpu.Cycle())
+	}
+
+	for _, cycle := range []int{1, 2} {
+		if _, err := d.AddBreakpoint(Breakpoint{Cycle: cycle}); err == nil {
+			t.Errorf("expected a breakpoint before cycle %d to be rejected after cycle 1", cycle)
+		}
+	}
+	if _, err := d.AddBreakpoint(Breakpoint{Cycle: 3}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestREPL(t *testing.T) {
+	cpu := loadTest(t)
+	var out strings.Builder
+	script := "break 20\ncontinue\nprint x\nnext\nprint x@19\nquit\n"
+	if err := NewDebugger(cpu, DeviceCRT).REPL(strings.NewReader(script), &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"breakpoint 1, before cycle 20\n", "x=21 during cycle 20\n", "during cycle 22:", "x=21 during cycle 19\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output is missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestCRTSize(t *testing.T) {
+	cpu := loadTest(t)
+	cpu.Run()
+
+	small := CRT{Width: 10, Height: 2, Sprite: "x"}
+	screen, err := small.Render(cpu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The program is written for a 40 pixel wide screen, so after the first
+	// row the sprite no longer lines up with the pixels being drawn
+	if want := "##..##..##\n....##....\n"; screen != want {
+		t.Errorf("got\n%swant\n%s", screen, want)
+	}
+
+	if _, err := (CRT{Width: 40, Height: 7, Sprite: "x"}).Render(cpu); err == nil {
+		t.Error("expected an error for a screen larger than the program draws")
+	}
+
+	img, err := DeviceCRT.Image(cpu, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 160 || b.Dy() != 24 {
+		t.Errorf("got a %dx%d image, want 160x24", b.Dx(), b.Dy())
+	}
+}
+
+func TestRender(t *testing.T) {
+	cpu := loadTest(t)
+	cpu.Run()
+
+	screen, err := DeviceCRT.Render(cpu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `##..##..##..##..##..##..##..##..##..##..
+###...###...###...###...###...###...###.
+####....####....####....####....####....
+#####.....#####.....#####.....#####.....
+######......######......######......####
+#######.......#######.......#######.....
+`
+	if screen != want {
+		t.Errorf("got\n%swant\n%s", screen, want)
+	}
+
+	// The example draws a pattern rather than letters
+	var unknown *ocr.UnknownGlyphError
+	if _, err := DeviceCRT.Read(cpu); !errors.As(err, &unknown) || unknown.Index != 0 {
+		t.Errorf("expected the first glyph to be unknown, got %v", err)
+	}
+}
+
+func TestShortProgram(t *testing.T) {
+	// The puzzle's first example only runs for 5 cycles, so it never reaches
+	// a cycle whose signal strength counts, and draws less than one row
+	program := "noop\naddx 3\naddx -5\n"
+	cpu, err := Load(strings.NewReader(program), DeviceInstructionSet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu.Run()
+	if x, _ := cpu.Value("x", 5); cpu.Cycle() != 5 || x != 4 {
+		t.Errorf("got x=%d during cycle 5 after %d cycles, want x=4 after 5", x, cpu.Cycle())
+	}
+
+	for part, solve := range map[int]func(r io.Reader) (string, error){1: Part1, 2: Part2} {
+		if answer, err := solve(strings.NewReader(program)); err == nil {
+			t.Errorf("part %d: expected an error, got %q", part, answer)
+		}
+	}
+}
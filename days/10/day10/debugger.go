@@ -0,0 +1,319 @@
+package day10
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const DEBUGGER_HELP = `commands:
+  s, step [n]           run n cycles (default 1)
+  n, next               run until the current instruction completes
+  c, continue           run until a breakpoint or the end of the program
+  b, break <cycle>      stop before the cycle runs (2 or later)
+  b, break <reg>=<n>    stop once the register holds n
+  d, delete <n>         remove breakpoint n
+  i, info               list the breakpoints
+  r, regs               show the registers and the current instruction
+  p, print <reg>[@<cycle>]
+                        show a register, now or during an earlier cycle
+  l, list [n]           show the n instructions around the current one
+  crt                   show the pixels drawn so far
+  q, quit               stop
+`
+
+// Breakpoint stops the CPU either before a given cycle runs, or once a
+// register holds a given value
+type Breakpoint struct {
+	// The cycle to stop before, or 0 to stop on a register instead
+	Cycle    int
+	Register string
+	Value    int
+}
+
+// Debugger runs a CPU until it reaches a breakpoint
+type Debugger struct {
+	cpu         *CPU
+	crt         CRT
+	breakpoints []Breakpoint
+}
+
+func NewDebugger(cpu *CPU, crt CRT) *Debugger {
+	return &Debugger{cpu: cpu, crt: crt, breakpoints: make([]Breakpoint, 0)}
+}
+
+// ParseBreakpoint reads a breakpoint written as a cycle, such as "20", or a
+// register and value, such as "x=5". The debugger starts before cycle 1, so the
+// earliest cycle to stop before is 2.
+func ParseBreakpoint(s string) (Breakpoint, error) {
+	if name, value, ok := strings.Cut(s, "="); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || name == "" {
+			return Breakpoint{}, fmt.Errorf("invalid breakpoint %q", s)
+		}
+		return Breakpoint{Register: name, Value: n}, nil
+	}
+	cycle, err := strconv.Atoi(s)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("invalid breakpoint %q", s)
+	}
+	if cycle < 2 {
+		return Breakpoint{}, fmt.Errorf("invalid breakpoint %q: the program starts before cycle 1, so the first cycle to stop before is 2", s)
+	}
+	return Breakpoint{Cycle: cycle}, nil
+}
+
+func (b Breakpoint) String() string {
+	if b.Cycle != 0 {
+		return fmt.Sprintf("before cycle %d", b.Cycle)
+	}
+	return fmt.Sprintf("when %s=%d", b.Register, b.Value)
+}
+
+// AddBreakpoint adds a breakpoint, returning its number. A cycle breakpoint
+// must be after the next cycle, since continuing always runs that one.
+func (d *Debugger) AddBreakpoint(b Breakpoint) (int, error) {
+	if b.Cycle != 0 && b.Cycle <= d.cpu.cycle+1 {
+		return 0, fmt.Errorf("can't stop before cycle %d, since the next cycle is %d", b.Cycle, d.cpu.cycle+1)
+	}
+	if b.Cycle == 0 {
+		if _, ok := d.cpu.isa.RegisterIndex(b.Register); !ok {
+			return 0, fmt.Errorf("no register %q", b.Register)
+		}
+	}
+	d.breakpoints = append(d.breakpoints, b)
+	return len(d.breakpoints), nil
+}
+
+// DeleteBreakpoint removes the numbered breakpoint. Later breakpoints keep
+// their numbers.
+func (d *Debugger) DeleteBreakpoint(n int) error {
+	if n < 1 || n > len(d.breakpoints) || d.breakpoints[n-1] == (Breakpoint{}) {
+		return fmt.Errorf("no breakpoint %d", n)
+	}
+	d.breakpoints[n-1] = Breakpoint{}
+	return nil
+}
+
+// Continue runs the CPU until it reaches a breakpoint, returning its number,
+// or until the program ends, returning 0. It always runs at least one cycle.
+func (d *Debugger) Continue() int {
+	for {
+		before := append([]int{}, d.cpu.registers...)
+		if !d.cpu.Tick() {
+			return 0
+		}
+		if hit := d.hit(before); hit != 0 {
+			return hit
+		}
+	}
+}
+
+// hit returns the number of the first breakpoint reached by the last cycle,
+// given the registers before it ran. Register breakpoints only fire when the
+// register changes to the value, so continuing doesn't stop straight away.
+func (d *Debugger) hit(before []int) int {
+	for i, b := range d.breakpoints {
+		switch {
+		case b == (Breakpoint{}):
+			{
+				continue
+			}
+		case b.Cycle != 0:
+			{
+				if d.cpu.cycle+1 == b.Cycle {
+					return i + 1
+				}
+			}
+		default:
+			{
+				index, _ := d.cpu.isa.RegisterIndex(b.Register)
+				if d.cpu.registers[index] == b.Value && before[index] != b.Value {
+					return i + 1
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// REPL reads debugger commands from in until it runs out or reads quit,
+// showing the state of the CPU after each one
+func (d *Debugger) REPL(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	fmt.Fprint(out, DEBUGGER_HELP)
+	fmt.Fprintln(out, d.cpu)
+	for {
+		fmt.Fprint(out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			args = []string{"step"}
+		}
+		if args[0] == "q" || args[0] == "quit" {
+			return nil
+		}
+		if err := d.command(args, out); err != nil {
+			fmt.Fprintf(out, "%s: %s\n", args[0], err)
+		}
+	}
+}
+
+func (d *Debugger) command(args []string, out io.Writer) error {
+	switch args[0] {
+	case "s", "step":
+		{
+			n, err := optionalCount(args)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < n && d.cpu.Tick(); i++ {
+			}
+			fmt.Fprintln(out, d.cpu)
+		}
+	case "n", "next":
+		{
+			if instruction, done, ok := d.cpu.Current(); ok {
+				for i := done; i < instruction.op.Cycles; i++ {
+					d.cpu.Tick()
+				}
+			}
+			fmt.Fprintln(out, d.cpu)
+		}
+	case "c", "continue":
+		{
+			if hit := d.Continue(); hit != 0 {
+				fmt.Fprintf(out, "breakpoint %d, %s\n", hit, d.breakpoints[hit-1])
+			}
+			fmt.Fprintln(out, d.cpu)
+		}
+	case "b", "break":
+		{
+			if len(args) != 2 {
+				return errors.New("usage: break <cycle> or break <reg>=<n>")
+			}
+			b, err := ParseBreakpoint(args[1])
+			if err != nil {
+				return err
+			}
+			n, err := d.AddBreakpoint(b)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "breakpoint %d, %s\n", n, b)
+		}
+	case "d", "delete":
+		{
+			if len(args) != 2 {
+				return errors.New("usage: delete <n>")
+			}
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid breakpoint number %q", args[1])
+			}
+			return d.DeleteBreakpoint(n)
+		}
+	case "i", "info":
+		{
+			for i, b := range d.breakpoints {
+				if b != (Breakpoint{}) {
+					fmt.Fprintf(out, "%d: %s\n", i+1, b)
+				}
+			}
+		}
+	case "r", "regs":
+		{
+			fmt.Fprintln(out, d.cpu)
+		}
+	case "p", "print":
+		{
+			if len(args) != 2 {
+				return errors.New("usage: print <reg>[@<cycle>]")
+			}
+			name, when, found := strings.Cut(args[1], "@")
+			cycle := d.cpu.cycle + 1
+			if found {
+				n, err := strconv.Atoi(when)
+				if err != nil {
+					return fmt.Errorf("invalid cycle %q", when)
+				}
+				cycle = n
+			}
+			value, err := d.cpu.Value(name, cycle)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%s=%d during cycle %d\n", name, value, cycle)
+		}
+	case "l", "list":
+		{
+			n, err := optionalCount(args)
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				n = 5
+			}
+			d.list(out, n)
+		}
+	case "crt":
+		{
+			pixels, drawn, err := d.crt.Draw(d.cpu)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%s%d of %d pixels drawn\n", renderPixels(pixels), drawn, d.crt.Width*d.crt.Height)
+		}
+	default:
+		{
+			fmt.Fprint(out, DEBUGGER_HELP)
+		}
+	}
+	return nil
+}
+
+// list shows the instructions around the current one, marking it with >
+func (d *Debugger) list(out io.Writer, n int) {
+	start := maxInt(d.cpu.pc-n/2, 0)
+	end := minInt(start+n, len(d.cpu.program))
+	for i := start; i < end; i++ {
+		marker := " "
+		if i == d.cpu.pc {
+			marker = ">"
+		}
+		instruction := d.cpu.program[i]
+		fmt.Fprintf(out, "%s %4d  %s\n", marker, instruction.line, instruction)
+	}
+}
+
+// optionalCount reads the count following a command, which defaults to 1
+func optionalCount(args []string) (int, error) {
+	if len(args) < 2 {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number %q", args[1])
+	}
+	return n, nil
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
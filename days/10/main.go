@@ -1,135 +1,82 @@
 package main
 
 import (
-	"errors"
+	"flag"
 	"fmt"
+	"image/png"
 	"os"
-	"strconv"
-	"strings"
-)
-
-type Instruction struct {
-	cmd string
-	arg int
-}
-
-type CPU struct {
-	x             int
-	history       []int
-	displayWidth  int
-	displayHeight int
-}
 
-func check(e error) {
-	if e != nil {
-		panic(e)
-	}
-}
+	"github.com/FaideWW/aoc-2022/days/10/day10"
+	"github.com/FaideWW/aoc-2022/solver"
+)
 
 func main() {
-	input := strings.TrimSpace(readInputFile(os.Args[1]))
-	instructions := parseInput(input)
-
-	cpu := CPU{
-		x:             1,
-		history:       make([]int, 0),
-		displayWidth:  40,
-		displayHeight: 6,
+	debug := flag.Bool("debug", false, "step through the program in a debugger")
+	screen := flag.Bool("screen", false, "print the pixels on the screen instead of reading the letters")
+	width := flag.Int("width", day10.CRT_WIDTH, "the width of the screen, in pixels")
+	height := flag.Int("height", day10.CRT_HEIGHT, "the height of the screen, in pixels")
+	image := flag.String("png", "", "write the screen to this PNG file")
+	scale := flag.Int("scale", 8, "the size of each pixel of the screen in the PNG")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [--debug] [--screen] [--width n] [--height n] [--png file] <input file>\n", os.Args[0])
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	cpu.executeInstructions(instructions)
-
-	timestamps := []int{20, 60, 100, 140, 180, 220}
-	sum := 0
-
-	for _, t := range timestamps {
-		strength := cpu.getSignalStrength(t)
-		fmt.Printf("signal strength at %d: %d\n", t, strength)
-		sum += strength
+	crt := day10.CRT{Width: *width, Height: *height, Sprite: day10.DeviceCRT.Sprite}
+	if !*debug && !*screen && *image == "" && crt == day10.DeviceCRT {
+		solver.Main(solver.Day{Part1: day10.Part1, Part2: day10.Part2})
+		return
 	}
 
-	fmt.Printf("total signal strength: %d\n", sum)
-
-	cpu.render()
-}
-
-func readInputFile(filename string) string {
-	dat, err := os.ReadFile(filename)
-	check(err)
-	return string(dat)
-}
-
-func parseInput(input string) []Instruction {
-	lines := strings.Split(input, "\n")
-
-	instructions := make([]Instruction, len(lines))
-
-	for i, line := range lines {
-		parts := strings.Split(line, " ")
-		switch parts[0] {
-		case "addx":
-			{
-				value, _ := strconv.Atoi(parts[1])
-				instructions[i] = Instruction{cmd: "addx", arg: value}
-			}
-		case "noop":
-			{
-				instructions[i] = Instruction{cmd: "noop"}
-			}
-		default:
-			{
-				panic(errors.New("unrecognized instruction"))
-			}
-		}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
 	}
-
-	return instructions
-}
-
-func (c *CPU) executeInstructions(instructions []Instruction) {
-	c.history = append(c.history, c.x)
-	for _, instruction := range instructions {
-		switch instruction.cmd {
-
-		case "addx":
-			{
-				c.history = append(c.history, c.x)
-				c.x += instruction.arg
-				c.history = append(c.history, c.x)
-			}
-		case "noop":
-			{
-				c.history = append(c.history, c.x)
-			}
-		default:
-			{
-				panic(errors.New("unrecognized instruction"))
-			}
-		}
+	if err := run(flag.Arg(0), *debug, crt, *image, *scale); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
 
-func (c *CPU) getSignalStrength(t int) int {
-	if t > len(c.history) {
-		panic(errors.New("time is outside history"))
+func run(path string, debug bool, crt day10.CRT, image string, scale int) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cpu, err := day10.Load(f, day10.DeviceInstructionSet())
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
 	}
 
-	return c.history[t-1] * t
-}
+	if debug {
+		if err := day10.NewDebugger(cpu, crt).REPL(os.Stdin, os.Stdout); err != nil {
+			return err
+		}
+	} else {
+		cpu.Run()
+		screen, err := crt.Render(cpu)
+		if err != nil {
+			return err
+		}
+		fmt.Print(screen)
+	}
 
-func (c *CPU) render() {
-	currentCycle := 0
-	for y := 0; y < c.displayHeight; y++ {
-		line := ""
-		for x := 0; x < c.displayWidth; x++ {
-			currentCycle = y*c.displayWidth + x
-			spritePosition := c.history[currentCycle]
-			if x-spritePosition < 2 && spritePosition-x < 2 {
-				line += "#"
-			} else {
-				line += "."
-			}
+	if image != "" {
+		img, err := crt.Image(cpu, scale)
+		if err != nil {
+			return err
+		}
+		out, err := os.Create(image)
+		if err != nil {
+			return err
+		}
+		err = png.Encode(out, img)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
 		}
-		fmt.Println(line)
+		return err
 	}
+	return nil
 }
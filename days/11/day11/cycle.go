@@ -0,0 +1,183 @@
+package day11
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+// ErrNoCycle is returned when worry levels can't be reduced, since items
+// aren't then limited to a finite number of states and may never repeat
+var ErrNoCycle = errors.New("worry levels can't be reduced, so items may never repeat themselves")
+
+// itemState is where an item is at the start of a round, and how worried it
+// makes you
+type itemState struct {
+	monkey int
+	worry  int
+}
+
+// Trajectory is the path of a single item through the monkeys, round by round,
+// until it is back in a state it has been in before. Items never affect each
+// other, and with worry levels reduced there are only so many states an item
+// can be in, so every item eventually goes round in a cycle.
+type Trajectory struct {
+	// The number of rounds before the cycle starts
+	Start int
+	// The number of rounds in the cycle
+	Length int
+	// totals[r][m] is the number of times monkey m has inspected the item
+	// during the first r rounds, up to the end of the first pass of the cycle
+	totals [][]int
+}
+
+// TraceItem follows an item from a monkey until its trajectory starts to repeat
+func TraceItem(monkeys []Monkey, monkey int, worry int) (*Trajectory, error) {
+	if monkey < 0 || monkey >= len(monkeys) {
+		return nil, fmt.Errorf("no monkey %d", monkey)
+	}
+	modulus, err := cycleModulus(monkeys)
+	if err != nil {
+		return nil, err
+	}
+	return traceItem(monkeys, itemState{monkey: monkey, worry: reduce(worry, modulus)}, modulus)
+}
+
+func traceItem(monkeys []Monkey, state itemState, modulus int) (*Trajectory, error) {
+	seen := map[itemState]int{state: 0}
+	totals := [][]int{make([]int, len(monkeys))}
+	for {
+		counts := append([]int{}, totals[len(totals)-1]...)
+		next, err := playRound(monkeys, state, modulus, counts)
+		if err != nil {
+			return nil, err
+		}
+		totals = append(totals, counts)
+		state = next
+
+		round := len(totals) - 1
+		if start, ok := seen[state]; ok {
+			return &Trajectory{Start: start, Length: round - start, totals: totals}, nil
+		}
+		seen[state] = round
+	}
+}
+
+// playRound moves an item through a single round, counting the inspections it
+// gets. An item thrown to a monkey later in the round is inspected again
+// before the round ends; otherwise it waits for the next round.
+func playRound(monkeys []Monkey, state itemState, modulus int, counts []int) (itemState, error) {
+	for {
+		monkey := monkeys[state.monkey]
+		worry, err := monkey.operation.eval(state.worry, modulus)
+		if err != nil {
+			return itemState{}, fmt.Errorf("monkey %d: %w", state.monkey, err)
+		}
+		counts[state.monkey]++
+
+		target := monkey.test.falseCase
+		if worry%monkey.test.condition == 0 {
+			target = monkey.test.trueCase
+		}
+		next := itemState{monkey: target, worry: worry}
+		if target < state.monkey {
+			return next, nil
+		}
+		state = next
+	}
+}
+
+// Inspections returns the number of times each monkey inspects the item
+// during the given number of rounds, which may be more than fits in an int
+func (t *Trajectory) Inspections(rounds *big.Int) []*big.Int {
+	inspections := make([]*big.Int, len(t.totals[0]))
+	if rounds.Cmp(big.NewInt(int64(t.Start+t.Length))) <= 0 {
+		for m, total := range t.totals[rounds.Int64()] {
+			inspections[m] = big.NewInt(int64(total))
+		}
+		return inspections
+	}
+
+	// Every pass through the cycle adds the same inspections, and the rounds
+	// left over follow the start of the cycle again
+	cycles, remainder := new(big.Int).DivMod(new(big.Int).Sub(rounds, big.NewInt(int64(t.Start))), big.NewInt(int64(t.Length)), new(big.Int))
+	first, last := t.totals[t.Start], t.totals[t.Start+int(remainder.Int64())]
+	end := t.totals[t.Start+t.Length]
+	for m := range inspections {
+		perCycle := big.NewInt(int64(end[m] - first[m]))
+		inspections[m] = perCycle.Mul(perCycle, cycles)
+		inspections[m].Add(inspections[m], big.NewInt(int64(last[m])))
+	}
+	return inspections
+}
+
+// Extrapolate returns the number of items each monkey inspects during the
+// given number of rounds without relief, by finding each item's cycle rather
+// than playing every round. Both the rounds and the counts can be larger than
+// an int.
+func Extrapolate(monkeys []Monkey, rounds *big.Int) ([]*big.Int, error) {
+	if rounds.Sign() < 0 {
+		return nil, fmt.Errorf("invalid number of rounds %s", rounds)
+	}
+	modulus, err := cycleModulus(monkeys)
+	if err != nil {
+		return nil, err
+	}
+
+	inspections := make([]*big.Int, len(monkeys))
+	for m := range inspections {
+		inspections[m] = new(big.Int)
+	}
+	// Items that start out the same share a trajectory
+	trajectories := make(map[itemState]*Trajectory)
+	for i, monkey := range monkeys {
+		for _, item := range monkey.items {
+			state := itemState{monkey: i, worry: reduce(item, modulus)}
+			t, ok := trajectories[state]
+			if !ok {
+				if t, err = traceItem(monkeys, state, modulus); err != nil {
+					return nil, err
+				}
+				trajectories[state] = t
+				solver.Debugf(solver.DEBUG, "item %d held by monkey %d repeats every %d rounds after round %d\n", item, i, t.Length, t.Start)
+			}
+			for m, count := range t.Inspections(rounds) {
+				inspections[m].Add(inspections[m], count)
+			}
+		}
+	}
+	return inspections, nil
+}
+
+// BigMonkeyBusiness returns the product of the two highest inspection counts
+func BigMonkeyBusiness(inspections []*big.Int) *big.Int {
+	first, second := new(big.Int), new(big.Int)
+	for _, count := range inspections {
+		switch {
+		case count.Cmp(first) > 0:
+			{
+				first, second = count, first
+			}
+		case count.Cmp(second) > 0:
+			{
+				second = count
+			}
+		}
+	}
+	return new(big.Int).Mul(first, second)
+}
+
+// cycleModulus returns the number worry levels are reduced modulo without
+// relief, which bounds the states an item can be in
+func cycleModulus(monkeys []Monkey) (int, error) {
+	if anyDivides(monkeys) {
+		return 0, ErrNoCycle
+	}
+	modulus := calculateMonkeyModulo(monkeys)
+	if modulus == 0 {
+		return 0, ErrNoCycle
+	}
+	return modulus, nil
+}
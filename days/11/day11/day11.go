@@ -0,0 +1,203 @@
+package day11
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+const PART1_ROUNDS = 20
+const PART1_RELIEF = 3
+const PART2_ROUNDS = 10000
+const PART2_RELIEF = 1
+
+type Test struct {
+	condition int
+	trueCase  int
+	falseCase int
+}
+
+// Monkey is a monkey's notes: the items it starts with, how it changes their
+// worry levels and who it throws them to
+type Monkey struct {
+	items     []int
+	operation Expr
+	test      Test
+}
+
+// Part1 returns the level of monkey business after 20 rounds with relief
+func Part1(r io.Reader) (string, error) {
+	monkeys, err := ReadMonkeys(r)
+	if err != nil {
+		return "", err
+	}
+	monkeyBusiness, err := Simulate(monkeys, PART1_ROUNDS, PART1_RELIEF, false)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(monkeyBusiness), nil
+}
+
+// Part2 returns the level of monkey business after 10000 rounds without relief
+func Part2(r io.Reader) (string, error) {
+	monkeys, err := ReadMonkeys(r)
+	if err != nil {
+		return "", err
+	}
+	monkeyBusiness, err := Simulate(monkeys, PART2_ROUNDS, PART2_RELIEF, false)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(monkeyBusiness), nil
+}
+
+// ReadMonkeys reads every monkey's notes
+func ReadMonkeys(r io.Reader) ([]Monkey, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(strings.TrimSpace(input))
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+// Simulate returns the level of monkey business after the given number of
+// rounds
+func Simulate(monkeys []Monkey, rounds int, relief int, exact bool) (int, error) {
+	s, err := NewSimulation(monkeys, relief, exact)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.Run(rounds, nil); err != nil {
+		return 0, err
+	}
+	return s.MonkeyBusiness(), nil
+}
+
+var (
+	monkeyPattern  = regexp.MustCompile(`Monkey (\d+):`)
+	testPattern    = regexp.MustCompile(`Test: divisible by (\d+)`)
+	ifTruePattern  = regexp.MustCompile(`If true: throw to monkey (\d+)`)
+	ifFalsePattern = regexp.MustCompile(`If false: throw to monkey (\d+)`)
+)
+
+func parseInput(input string) ([]Monkey, error) {
+	blocks := parse.Paragraphs(input)
+	monkeys := make([]Monkey, len(blocks))
+
+	for i, block := range blocks {
+		monkey, err := parseMonkey(block, i)
+		if err != nil {
+			return nil, err
+		}
+		monkeys[i] = monkey
+	}
+
+	if len(monkeys) < 2 {
+		return nil, parse.Errorf(1, 0, "expected at least two monkeys, got %d", len(monkeys))
+	}
+	// now that every monkey is known, make sure the throws land somewhere
+	for i, block := range blocks {
+		for j, target := range []int{monkeys[i].test.trueCase, monkeys[i].test.falseCase} {
+			if target < 0 || target >= len(monkeys) || target == i {
+				return nil, block[4+j].Errorf("monkey %d can't throw to monkey %d", i, target)
+			}
+		}
+	}
+
+	return monkeys, nil
+}
+
+func parseMonkey(lines []parse.Field, index int) (Monkey, error) {
+	// structure of monkey notes:
+	// Monkey [n]:
+	//   Starting items: [...]
+	//   Operation: [...]
+	//   Test: divisible by [n]
+	//     If true: throw to monkey [n]
+	//     If false: throw to monkey [n]
+	if len(lines) != 6 {
+		return Monkey{}, lines[0].Errorf("expected 6 lines of notes for each monkey, got %d", len(lines))
+	}
+	for i := range lines {
+		lines[i] = lines[i].TrimSpace()
+	}
+
+	header, err := lines[0].Match(monkeyPattern, "\"Monkey <n>:\"")
+	if err != nil {
+		return Monkey{}, err
+	}
+	if n, err := header[0].Int(); err != nil || n != index {
+		return Monkey{}, header[0].Errorf("expected monkey %d", index)
+	}
+
+	items, err := parseItems(lines[1])
+	if err != nil {
+		return Monkey{}, err
+	}
+	operation, err := parseOperation(lines[2])
+	if err != nil {
+		return Monkey{}, err
+	}
+	test, err := parseTest(lines[3:])
+	if err != nil {
+		return Monkey{}, err
+	}
+
+	return Monkey{
+		items:     items,
+		operation: operation,
+		test:      test,
+	}, nil
+}
+
+func parseItems(line parse.Field) ([]int, error) {
+	itemsStr, err := line.CutPrefix("Starting items:")
+	if err != nil {
+		return nil, err
+	}
+	itemsStr = itemsStr.TrimSpace()
+	if itemsStr.Text == "" {
+		return []int{}, nil
+	}
+	return parse.Ints(itemsStr.Split(", "))
+}
+
+func parseOperation(line parse.Field) (Expr, error) {
+	expression, err := line.CutPrefix("Operation: new =")
+	if err != nil {
+		return nil, err
+	}
+	return parseExpr(expression)
+}
+
+func parseTest(lines []parse.Field) (Test, error) {
+	values := make([]int, 3)
+	patterns := []*regexp.Regexp{testPattern, ifTruePattern, ifFalsePattern}
+	wants := []string{"\"Test: divisible by <n>\"", "\"If true: throw to monkey <n>\"", "\"If false: throw to monkey <n>\""}
+	for i, line := range lines {
+		match, err := line.Match(patterns[i], wants[i])
+		if err != nil {
+			return Test{}, err
+		}
+		if values[i], err = match[0].Int(); err != nil {
+			return Test{}, err
+		}
+	}
+	if values[0] == 0 {
+		return Test{}, lines[0].Errorf("can't test for divisibility by zero")
+	}
+
+	return Test{
+		condition: values[0],
+		trueCase:  values[1],
+		falseCase: values[2],
+	}, nil
+}
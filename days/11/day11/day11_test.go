@@ -0,0 +1,252 @@
+package day11
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+func readTest(t *testing.T) []Monkey {
+	t.Helper()
+	f, err := os.Open("../test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	monkeys, err := ReadMonkeys(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return monkeys
+}
+
+func TestExpressions(t *testing.T) {
+	tests := []struct {
+		expression string
+		old        int
+		want       int
+	}{
+		{"old * 19", 79, 1501},
+		{"old + old", 5, 10},
+		{"old ^ 2", 12, 144},
+		{"(old + 3) * 2 - old / 2", 7, 17},
+		{"2 * (old - 10) ^ 3", 7, -54},
+		{"100 - old - 1", 10, 89},
+		{"old/4*4", 10, 8},
+	}
+	for _, test := range tests {
+		e, err := parseExpr(parse.Field{Text: test.expression, Line: 1, Col: 1})
+		if err != nil {
+			t.Errorf("%s: %s", test.expression, err)
+			continue
+		}
+		got, err := e.eval(test.old, 0)
+		if err != nil || got != test.want {
+			t.Errorf("%s with old = %d: got %d (%v), want %d", test.expression, test.old, got, err, test.want)
+		}
+		exact, err := e.evalBig(big.NewInt(int64(test.old)))
+		if err != nil || exact.Int64() != int64(test.want) {
+			t.Errorf("%s with old = %d: got %s (%v) with big numbers, want %d", test.expression, test.old, exact, err, test.want)
+		}
+		if !e.divides() {
+			if reduced, _ := e.eval(test.old, 23); reduced != reduce(test.want, 23) {
+				t.Errorf("%s with old = %d: got %d modulo 23, want %d", test.expression, test.old, reduced, reduce(test.want, 23))
+			}
+		}
+	}
+}
+
+func TestInvalidExpressions(t *testing.T) {
+	tests := []struct {
+		expression string
+		col        int
+	}{
+		{"old +", 6},
+		{"(old * 2", 9},
+		{"old * new", 7},
+		{"old / 0", 7},
+		{"old ^ old", 7},
+		{"old ^ 65", 7},
+		{"old ^ 2 ^ 2", 9},
+		{"old 2", 5},
+	}
+	for _, test := range tests {
+		_, err := parseExpr(parse.Field{Text: test.expression, Line: 1, Col: 1})
+		var parseErr *parse.Error
+		if !errors.As(err, &parseErr) || parseErr.Col != test.col {
+			t.Errorf("%s: expected an error at column %d, got %v", test.expression, test.col, err)
+		}
+	}
+
+	e, _ := parseExpr(parse.Field{Text: "old * old * old"})
+	if _, err := e.eval(1<<30, 0); !errors.Is(err, ErrOverflow) {
+		t.Errorf("expected an overflow, got %v", err)
+	}
+	e, _ = parseExpr(parse.Field{Text: "1 / (old - 3)"})
+	if _, err := e.eval(3, 0); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("expected division by zero, got %v", err)
+	}
+}
+
+func TestReductionMatchesExact(t *testing.T) {
+	monkeys := readTest(t)
+	got, err := Verify(monkeys, 300, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := mustSimulate(t, monkeys, 300); got != want {
+		t.Errorf("got %d with exact worry levels, want %d", got, want)
+	}
+
+	// Subtraction and powers can be reduced too, but division can't
+	input := strings.NewReader(`Monkey 0:
+  Starting items: 4, 9
+  Operation: new = (old - 7) ^ 2
+  Test: divisible by 5
+    If true: throw to monkey 1
+    If false: throw to monkey 1
+
+Monkey 1:
+  Starting items: 2
+  Operation: new = 3 - old * 2
+  Test: divisible by 3
+    If true: throw to monkey 0
+    If false: throw to monkey 0
+`)
+	monkeys, err = ReadMonkeys(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ := NewSimulation(monkeys, 1, false)
+	if s.Modulus() != 15 {
+		t.Errorf("got modulus %d, want 15", s.Modulus())
+	}
+	if _, err := Verify(monkeys, 12, 1); err != nil {
+		t.Error(err)
+	}
+
+	monkeys[1].operation = Binary{Operator: '/', Left: Old{}, Right: Literal(2)}
+	if s, _ := NewSimulation(monkeys, 1, false); s.Modulus() != 0 {
+		t.Errorf("worry levels are reduced modulo %d despite division", s.Modulus())
+	}
+}
+
+func mustSimulate(t *testing.T, monkeys []Monkey, rounds int) int {
+	t.Helper()
+	monkeyBusiness, err := Simulate(monkeys, rounds, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return monkeyBusiness
+}
+
+func TestTrace(t *testing.T) {
+	s, err := NewSimulation(readTest(t), PART1_RELIEF, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	trace := NewTraceWriter(&b)
+	if err := s.Run(2, trace.Record); err != nil {
+		t.Fatal(err)
+	}
+	if err := trace.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "round,monkey,inspections,total\n1,0,2,2\n1,1,4,4\n1,2,3,3\n1,3,5,5\n2,0,4,6\n2,1,6,10\n2,2,1,4\n2,3,5,10\n"
+	if b.String() != want {
+		t.Errorf("got\n%swant\n%s", b.String(), want)
+	}
+}
+
+func TestExtrapolateMatchesBruteForce(t *testing.T) {
+	monkeys := readTest(t)
+	s, err := NewSimulation(monkeys, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(rounds int) {
+		inspections, err := Extrapolate(monkeys, big.NewInt(int64(rounds)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for m, want := range s.Inspections() {
+			if inspections[m].Cmp(big.NewInt(int64(want))) != 0 {
+				t.Fatalf("after %d rounds, monkey %d has inspected %s items, want %d", rounds, m, inspections[m], want)
+			}
+		}
+	}
+	check(0)
+	for s.Round() < PART2_ROUNDS {
+		if err := s.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if s.Round() <= 500 || s.Round()%499 == 0 {
+			check(s.Round())
+		}
+	}
+	check(PART2_ROUNDS)
+
+	inspections, _ := Extrapolate(monkeys, big.NewInt(PART2_ROUNDS))
+	if got := BigMonkeyBusiness(inspections).String(); got != "2713310158" {
+		t.Errorf("got monkey business %s, want 2713310158", got)
+	}
+}
+
+func TestTrajectory(t *testing.T) {
+	monkeys := readTest(t)
+	trajectory, err := TraceItem(monkeys, 0, 79)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Follow the item on its own for a few passes through its cycle
+	alone := append([]Monkey{}, monkeys...)
+	for i := range alone {
+		alone[i].items = nil
+	}
+	alone[0].items = []int{79}
+	s, err := NewSimulation(alone, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for s.Round() < trajectory.Start+3*trajectory.Length+1 {
+		if err := s.Step(); err != nil {
+			t.Fatal(err)
+		}
+		for m, got := range trajectory.Inspections(big.NewInt(int64(s.Round()))) {
+			if want := s.Inspections()[m]; got.Cmp(big.NewInt(int64(want))) != 0 {
+				t.Fatalf("after %d rounds, monkey %d has inspected the item %s times, want %d", s.Round(), m, got, want)
+			}
+		}
+	}
+
+	// Far more rounds than fit in an int, landing at the start of the cycle
+	cycles, _ := new(big.Int).SetString("1000000000000000000000000", 10)
+	rounds := new(big.Int).Mul(cycles, big.NewInt(int64(trajectory.Length)))
+	rounds.Add(rounds, big.NewInt(int64(trajectory.Start)))
+	start := trajectory.Inspections(big.NewInt(int64(trajectory.Start)))
+	end := trajectory.Inspections(big.NewInt(int64(trajectory.Start + trajectory.Length)))
+	for m, got := range trajectory.Inspections(rounds) {
+		want := new(big.Int).Sub(end[m], start[m])
+		want.Mul(want, cycles).Add(want, start[m])
+		if got.Cmp(want) != 0 {
+			t.Errorf("after %s rounds, monkey %d has inspected the item %s times, want %s", rounds, m, got, want)
+		}
+	}
+
+	if _, err := Extrapolate(monkeys, big.NewInt(-1)); err == nil {
+		t.Error("extrapolated a negative number of rounds")
+	}
+
+	monkeys[2].operation = Binary{Operator: '/', Left: Old{}, Right: Literal(2)}
+	if _, err := Extrapolate(monkeys, big.NewInt(1_000_000_000_000)); !errors.Is(err, ErrNoCycle) {
+		t.Errorf("expected no cycle once an operation divides, got %v", err)
+	}
+}
@@ -0,0 +1,410 @@
+package day11
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+// The largest exponent an operation may use
+const MAX_EXPONENT = 64
+
+// ErrOverflow is returned when a worry level no longer fits in an int. Exact
+// big numbers don't have this problem.
+var ErrOverflow = errors.New("worry level overflowed; try big numbers instead")
+
+// ErrDivisionByZero is returned when an operation divides by zero
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Expr is the expression an operation uses to work out the new worry level
+// from the old one
+type Expr interface {
+	// eval works out the new worry level, modulo m unless it is 0. old must
+	// already be reduced.
+	eval(old int, m int) (int, error)
+	evalBig(old *big.Int) (*big.Int, error)
+	// divides returns whether the expression divides, since that stops worry
+	// levels from being reduced
+	divides() bool
+	String() string
+}
+
+// Old is the old worry level
+type Old struct{}
+
+// Literal is a number written in the operation
+type Literal int
+
+// Binary combines two expressions with +, -, * or /. Division truncates
+// towards zero.
+type Binary struct {
+	Operator    byte
+	Left, Right Expr
+}
+
+// Power raises an expression to a fixed exponent, such as old ^ 2
+type Power struct {
+	Base     Expr
+	Exponent int
+}
+
+// exprParser reads an expression one character at a time, keeping track of
+// its position for error messages. The grammar, loosest first, is
+//
+//	sum     = product { ("+" | "-") product }
+//	product = power { ("*" | "/") power }
+//	power   = primary [ "^" number ]
+//	primary = "old" | number | "(" sum ")"
+type exprParser struct {
+	input parse.Field
+	pos   int
+}
+
+func parseExpr(input parse.Field) (Expr, error) {
+	p := exprParser{input: input}
+	e, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != 0 {
+		return nil, p.errorf("unexpected %q after the end of the expression", p.peek())
+	}
+	return e, nil
+}
+
+func (p *exprParser) errorf(format string, a ...any) error {
+	return parse.Errorf(p.input.Line, p.input.Col+p.pos, format, a...)
+}
+
+// peek skips any spaces, then returns the next character, or 0 at the end of
+// the input
+func (p *exprParser) peek() byte {
+	for p.pos < len(p.input.Text) && p.input.Text[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.input.Text) {
+		return 0
+	}
+	return p.input.Text[p.pos]
+}
+
+func (p *exprParser) parseSum() (Expr, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '+' || p.peek() == '-' {
+		operator := p.peek()
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = Binary{Operator: operator, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseProduct() (Expr, error) {
+	left, err := p.parsePower()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '*' || p.peek() == '/' {
+		operator := p.peek()
+		p.pos++
+		p.peek()
+		start := p.pos
+		right, err := p.parsePower()
+		if err != nil {
+			return nil, err
+		}
+		if operator == '/' && right == Literal(0) {
+			return nil, parse.Errorf(p.input.Line, p.input.Col+start, "division by zero")
+		}
+		left = Binary{Operator: operator, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parsePower() (Expr, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != '^' {
+		return base, nil
+	}
+	p.pos++
+	p.peek()
+	start := p.pos
+	exponent, ok := p.parseNumber()
+	if !ok {
+		return nil, p.errorf("expected a number for the exponent")
+	}
+	if exponent > MAX_EXPONENT {
+		return nil, parse.Errorf(p.input.Line, p.input.Col+start, "exponent %d is larger than %d", exponent, MAX_EXPONENT)
+	}
+	return Power{Base: base, Exponent: exponent}, nil
+}
+
+func (p *exprParser) parsePrimary() (Expr, error) {
+	switch c := p.peek(); {
+	case c == '(':
+		{
+			p.pos++
+			e, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			if p.peek() != ')' {
+				return nil, p.errorf("expected ')'")
+			}
+			p.pos++
+			return e, nil
+		}
+	case c >= '0' && c <= '9':
+		{
+			start := p.pos
+			n, ok := p.parseNumber()
+			if !ok {
+				return nil, parse.Errorf(p.input.Line, p.input.Col+start, "invalid number %q", p.input.Text[start:p.pos])
+			}
+			return Literal(n), nil
+		}
+	case c == 'o' && p.pos+3 <= len(p.input.Text) && p.input.Text[p.pos:p.pos+3] == "old":
+		{
+			p.pos += 3
+			return Old{}, nil
+		}
+	case c == 0:
+		{
+			return nil, p.errorf("unexpected end of expression")
+		}
+	default:
+		{
+			return nil, p.errorf("expected old, a number or '(', got %q", c)
+		}
+	}
+}
+
+// parseNumber reads a run of digits, returning false if there are none or
+// they don't fit in an int
+func (p *exprParser) parseNumber() (int, bool) {
+	start := p.pos
+	for p.pos < len(p.input.Text) && p.input.Text[p.pos] >= '0' && p.input.Text[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.input.Text[start:p.pos])
+	return n, err == nil
+}
+
+func (Old) eval(old int, m int) (int, error) {
+	return old, nil
+}
+
+func (Old) evalBig(old *big.Int) (*big.Int, error) {
+	return old, nil
+}
+
+func (Old) divides() bool {
+	return false
+}
+
+func (Old) String() string {
+	return "old"
+}
+
+func (l Literal) eval(old int, m int) (int, error) {
+	if m != 0 {
+		return int(l) % m, nil
+	}
+	return int(l), nil
+}
+
+func (l Literal) evalBig(old *big.Int) (*big.Int, error) {
+	return big.NewInt(int64(l)), nil
+}
+
+func (Literal) divides() bool {
+	return false
+}
+
+func (l Literal) String() string {
+	return fmt.Sprint(int(l))
+}
+
+func (b Binary) eval(old int, m int) (int, error) {
+	left, err := b.Left.eval(old, m)
+	if err != nil {
+		return 0, err
+	}
+	right, err := b.Right.eval(old, m)
+	if err != nil {
+		return 0, err
+	}
+
+	if m != 0 {
+		// Both sides are in [0, m), so the sum and difference fit in a uint64
+		// and the product can be reduced from 128 bits
+		switch b.Operator {
+		case '+':
+			{
+				return int((uint64(left) + uint64(right)) % uint64(m)), nil
+			}
+		case '-':
+			{
+				return int((uint64(left) + uint64(m) - uint64(right)) % uint64(m)), nil
+			}
+		case '*':
+			{
+				return mulMod(left, right, m), nil
+			}
+		default:
+			{
+				panic(fmt.Sprintf("day11: can't reduce %q modulo %d", b, m))
+			}
+		}
+	}
+
+	switch b.Operator {
+	case '+':
+		{
+			sum := left + right
+			if (sum > left) != (right > 0) {
+				return 0, ErrOverflow
+			}
+			return sum, nil
+		}
+	case '-':
+		{
+			difference := left - right
+			if (difference < left) != (right > 0) {
+				return 0, ErrOverflow
+			}
+			return difference, nil
+		}
+	case '*':
+		{
+			return mulChecked(left, right)
+		}
+	case '/':
+		{
+			if right == 0 {
+				return 0, ErrDivisionByZero
+			}
+			if left == math.MinInt && right == -1 {
+				return 0, ErrOverflow
+			}
+			return left / right, nil
+		}
+	default:
+		{
+			panic(fmt.Sprintf("day11: unknown operator %q", b.Operator))
+		}
+	}
+}
+
+func (b Binary) evalBig(old *big.Int) (*big.Int, error) {
+	left, err := b.Left.evalBig(old)
+	if err != nil {
+		return nil, err
+	}
+	right, err := b.Right.evalBig(old)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(big.Int)
+	switch b.Operator {
+	case '+':
+		{
+			return result.Add(left, right), nil
+		}
+	case '-':
+		{
+			return result.Sub(left, right), nil
+		}
+	case '*':
+		{
+			return result.Mul(left, right), nil
+		}
+	case '/':
+		{
+			if right.Sign() == 0 {
+				return nil, ErrDivisionByZero
+			}
+			// Quo truncates like int division, rather than rounding down
+			return result.Quo(left, right), nil
+		}
+	default:
+		{
+			panic(fmt.Sprintf("day11: unknown operator %q", b.Operator))
+		}
+	}
+}
+
+func (b Binary) divides() bool {
+	return b.Operator == '/' || b.Left.divides() || b.Right.divides()
+}
+
+func (b Binary) String() string {
+	return fmt.Sprintf("(%s %c %s)", b.Left, b.Operator, b.Right)
+}
+
+func (p Power) eval(old int, m int) (int, error) {
+	base, err := p.Base.eval(old, m)
+	if err != nil {
+		return 0, err
+	}
+	result := 1
+	if m != 0 {
+		result %= m
+	}
+	for i := 0; i < p.Exponent; i++ {
+		if m != 0 {
+			result = mulMod(result, base, m)
+		} else if result, err = mulChecked(result, base); err != nil {
+			return 0, err
+		}
+	}
+	return result, nil
+}
+
+func (p Power) evalBig(old *big.Int) (*big.Int, error) {
+	base, err := p.Base.evalBig(old)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Exp(base, big.NewInt(int64(p.Exponent)), nil), nil
+}
+
+func (p Power) divides() bool {
+	return p.Base.divides()
+}
+
+func (p Power) String() string {
+	return fmt.Sprintf("%s ^ %d", p.Base, p.Exponent)
+}
+
+// mulMod multiplies a and b, which are both in [0, m), modulo m
+func mulMod(a int, b int, m int) int {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	return int(bits.Rem64(hi, lo, uint64(m)))
+}
+
+func mulChecked(a int, b int) (int, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, ErrOverflow
+	}
+	return product, nil
+}
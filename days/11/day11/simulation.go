@@ -0,0 +1,254 @@
+package day11
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+// Simulation plays out the monkeys' rounds, keeping worry levels either as
+// ints (reduced where possible) or as exact big numbers
+type Simulation struct {
+	monkeys []Monkey
+	relief  int
+	// The number worry levels are reduced modulo, or 0 if they can't be
+	modulus int
+	exact   bool
+	// The items each monkey holds, in whichever form is being used
+	items    [][]int
+	bigItems [][]*big.Int
+	// The number of items each monkey has inspected so far
+	inspections []int
+	round       int
+}
+
+// NewSimulation returns a simulation of the monkeys before the first round.
+// Worry levels are divided by relief after each inspection. Unless exact is
+// set, worry levels are kept modulo the lowest common multiple of the tests'
+// divisors whenever that doesn't change where items go: that is, when there is
+// no relief and no operation divides.
+func NewSimulation(monkeys []Monkey, relief int, exact bool) (*Simulation, error) {
+	if relief < 1 {
+		return nil, fmt.Errorf("invalid relief %d: worry levels must be divided by at least 1", relief)
+	}
+	s := &Simulation{
+		monkeys:     monkeys,
+		relief:      relief,
+		exact:       exact,
+		inspections: make([]int, len(monkeys)),
+	}
+
+	if !exact && relief == 1 && !anyDivides(monkeys) {
+		s.modulus = calculateMonkeyModulo(monkeys)
+	}
+	for _, monkey := range monkeys {
+		items := make([]int, len(monkey.items))
+		bigItems := make([]*big.Int, len(monkey.items))
+		for i, item := range monkey.items {
+			items[i] = item
+			if s.modulus != 0 {
+				items[i] = reduce(item, s.modulus)
+			}
+			bigItems[i] = big.NewInt(int64(item))
+		}
+		if exact {
+			s.bigItems = append(s.bigItems, bigItems)
+		} else {
+			s.items = append(s.items, items)
+		}
+	}
+	return s, nil
+}
+
+// Modulus returns the number worry levels are being reduced modulo, or 0 if
+// they aren't
+func (s *Simulation) Modulus() int {
+	return s.modulus
+}
+
+// Round returns the number of rounds played so far
+func (s *Simulation) Round() int {
+	return s.round
+}
+
+// Inspections returns the number of items each monkey has inspected so far
+func (s *Simulation) Inspections() []int {
+	return append([]int{}, s.inspections...)
+}
+
+// MonkeyBusiness returns the product of the two highest inspection counts
+func (s *Simulation) MonkeyBusiness() int {
+	return calculateMonkeyBusiness(s.inspections)
+}
+
+// Run plays the given number of rounds, calling after (if it isn't nil) after
+// each one
+func (s *Simulation) Run(rounds int, after func(s *Simulation) error) error {
+	for i := 0; i < rounds; i++ {
+		if err := s.Step(); err != nil {
+			return err
+		}
+		if after != nil {
+			if err := after(s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Step plays a single round: each monkey in turn inspects and throws every
+// item it holds
+func (s *Simulation) Step() error {
+	for i, monkey := range s.monkeys {
+		var err error
+		if s.exact {
+			err = s.throwBig(i, monkey)
+		} else {
+			err = s.throw(i, monkey)
+		}
+		if err != nil {
+			return fmt.Errorf("round %d, monkey %d: %w", s.round+1, i, err)
+		}
+	}
+	s.round++
+
+	if solver.Verbosity >= solver.TRACE {
+		s.printMonkeys()
+	}
+	return nil
+}
+
+func (s *Simulation) throw(i int, monkey Monkey) error {
+	for _, item := range s.items[i] {
+		newWorry, err := monkey.operation.eval(item, s.modulus)
+		if err != nil {
+			return err
+		}
+		newWorry /= s.relief
+		target := monkey.test.falseCase
+		if newWorry%monkey.test.condition == 0 {
+			target = monkey.test.trueCase
+		}
+		s.items[target] = append(s.items[target], newWorry)
+		s.inspections[i]++
+	}
+	s.items[i] = s.items[i][:0]
+	return nil
+}
+
+func (s *Simulation) throwBig(i int, monkey Monkey) error {
+	relief := big.NewInt(int64(s.relief))
+	remainder := new(big.Int)
+	for _, item := range s.bigItems[i] {
+		newWorry, err := monkey.operation.evalBig(item)
+		if err != nil {
+			return err
+		}
+		newWorry = new(big.Int).Quo(newWorry, relief)
+		target := monkey.test.falseCase
+		if remainder.Rem(newWorry, big.NewInt(int64(monkey.test.condition))).Sign() == 0 {
+			target = monkey.test.trueCase
+		}
+		s.bigItems[target] = append(s.bigItems[target], newWorry)
+		s.inspections[i]++
+	}
+	s.bigItems[i] = s.bigItems[i][:0]
+	return nil
+}
+
+func (s *Simulation) printMonkeys() {
+	solver.Debugf(solver.TRACE, "After round %d:\n", s.round)
+	for i := range s.monkeys {
+		items := make([]string, 0)
+		if s.exact {
+			for _, item := range s.bigItems[i] {
+				items = append(items, item.String())
+			}
+		} else {
+			for _, item := range s.items[i] {
+				items = append(items, fmt.Sprint(item))
+			}
+		}
+		solver.Debugf(solver.TRACE, "Monkey %d: %s\n", i, strings.Join(items, ", "))
+	}
+}
+
+// Verify plays the rounds twice, once with reduced worry levels and once with
+// exact ones, and checks that every monkey has inspected the same number of
+// items after each round. It returns the level of monkey business at the end.
+func Verify(monkeys []Monkey, rounds int, relief int) (int, error) {
+	reduced, err := NewSimulation(monkeys, relief, false)
+	if err != nil {
+		return 0, err
+	}
+	exact, err := NewSimulation(monkeys, relief, true)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < rounds; i++ {
+		if err := reduced.Step(); err != nil {
+			return 0, err
+		}
+		if err := exact.Step(); err != nil {
+			return 0, err
+		}
+		for m := range monkeys {
+			if reduced.inspections[m] != exact.inspections[m] {
+				return 0, fmt.Errorf("after round %d, monkey %d has inspected %d items with reduced worry levels, but %d with exact ones", i+1, m, reduced.inspections[m], exact.inspections[m])
+			}
+		}
+	}
+	solver.Debugf(solver.DEBUG, "%d rounds agree with worry levels modulo %d\n", rounds, reduced.modulus)
+	return exact.MonkeyBusiness(), nil
+}
+
+func anyDivides(monkeys []Monkey) bool {
+	for _, monkey := range monkeys {
+		if monkey.operation.divides() {
+			return true
+		}
+	}
+	return false
+}
+
+// reduce returns n modulo m, between 0 and m-1 even if n is negative
+func reduce(n int, m int) int {
+	return ((n % m) + m) % m
+}
+
+func calculateMonkeyBusiness(inspections []int) int {
+	sorted := append([]int{}, inspections...)
+	sort.Ints(sorted)
+
+	return sorted[len(sorted)-1] * sorted[len(sorted)-2]
+}
+
+// calculateMonkeyModulo returns the lowest common multiple of the tests'
+// divisors. Every test gives the same result for a worry level and its
+// remainder, and so does every operation that only adds, subtracts and
+// multiplies. It returns 0 if the multiple doesn't fit in an int.
+func calculateMonkeyModulo(monkeys []Monkey) int {
+	lcm := 1
+	for _, monkey := range monkeys {
+		divisor := monkey.test.condition / gcd(lcm, monkey.test.condition)
+		if lcm > math.MaxInt/divisor {
+			return 0
+		}
+		lcm *= divisor
+	}
+
+	return lcm
+}
+
+func gcd(a int, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,44 @@
+package day11
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// TraceWriter records how many items each monkey inspects during each round,
+// as CSV with a row per monkey per round
+type TraceWriter struct {
+	w *csv.Writer
+	// The inspection counts after the last round recorded
+	previous []int
+}
+
+func NewTraceWriter(w io.Writer) *TraceWriter {
+	return &TraceWriter{w: csv.NewWriter(w)}
+}
+
+// Record writes a row for each monkey for the round just played. It can be
+// passed straight to Simulation.Run.
+func (t *TraceWriter) Record(s *Simulation) error {
+	if t.previous == nil {
+		if err := t.w.Write([]string{"round", "monkey", "inspections", "total"}); err != nil {
+			return err
+		}
+		t.previous = make([]int, len(s.inspections))
+	}
+	for i, total := range s.inspections {
+		row := []string{fmt.Sprint(s.round), fmt.Sprint(i), fmt.Sprint(total - t.previous[i]), fmt.Sprint(total)}
+		if err := t.w.Write(row); err != nil {
+			return err
+		}
+	}
+	copy(t.previous, s.inspections)
+	return nil
+}
+
+// Flush writes any buffered rows, returning the first error from writing
+func (t *TraceWriter) Flush() error {
+	t.w.Flush()
+	return t.w.Error()
+}
@@ -1,226 +1,149 @@
 package main
 
 import (
-	"errors"
+	"flag"
 	"fmt"
+	"math"
+	"math/big"
 	"os"
-	"sort"
-	"strconv"
-	"strings"
-)
-
-const WORRY_DIVISOR = 1
-
-type Operation struct {
-	operator string
-	left     string
-	right    string
-	leftInt  int
-	rightInt int
-}
 
-type Test struct {
-	condition int
-	trueCase  int
-	falseCase int
-}
-
-type Monkey struct {
-	items       []int
-	operation   Operation
-	test        Test
-	inspections int
-}
+	"github.com/FaideWW/aoc-2022/days/11/day11"
+	"github.com/FaideWW/aoc-2022/solver"
+)
 
-func check(e error) {
-	if e != nil {
-		panic(e)
-	}
+// options are the settings for a run of one part
+type options struct {
+	// The number of rounds to play, or nil for each part's default
+	rounds *big.Int
+	relief int
+	exact  bool
+	verify bool
+	fast   bool
+	trace  string
 }
 
 func main() {
-	input := strings.TrimSpace(readInputFile(os.Args[1]))
-	fmt.Println(input)
-	monkeys := parseInput(input)
-	printMonkeys(&monkeys)
-	for i := 0; i < 10000; i++ {
-		doRound(&monkeys)
-	}
-	printMonkeys(&monkeys)
-
-	for i, m := range monkeys {
-		fmt.Printf("Monkey %d inspected items %d times.\n", i, m.inspections)
+	part := flag.Int("part", 0, "only run this part (1 or 2)")
+	rounds := new(big.Int)
+	flag.TextVar(rounds, "rounds", new(big.Int), fmt.Sprintf("the number of rounds to play (default %d for part 1, %d for part 2; any number with --fast)", day11.PART1_ROUNDS, day11.PART2_ROUNDS))
+	relief := flag.Int("relief", 0, fmt.Sprintf("divide worry levels by this after each inspection (default %d for part 1, %d for part 2)", day11.PART1_RELIEF, day11.PART2_RELIEF))
+	exact := flag.Bool("big", false, "keep exact worry levels as big numbers, instead of reducing them")
+	verify := flag.Bool("verify", false, "check after every round that reducing worry levels gives the same inspection counts as exact big numbers")
+	fast := flag.Bool("fast", false, "fast-forward by finding where each item starts repeating itself, instead of playing every round (only without relief, so this implies --part 2)")
+	trace := flag.String("trace", "", "write the inspections each monkey makes every round to this CSV file (needs --part)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [--part n] [--rounds n] [--relief n] [--big | --verify | --fast] [--trace file] <input file>\n", os.Args[0])
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	monkeyBusiness := calculateMonkeyBusiness(monkeys)
-	fmt.Printf("monkey business: %d\n", monkeyBusiness)
-}
-
-func readInputFile(filename string) string {
-	dat, err := os.ReadFile(filename)
-	check(err)
-	return string(dat)
-}
-
-func parseInput(input string) []Monkey {
-	blocks := strings.Split(input, "\n\n")
-	monkeys := make([]Monkey, len(blocks))
-
-	for i, block := range blocks {
-		monkeys[i] = parseMonkey(block)
+	if flag.NFlag() == 0 {
+		solver.Main(solver.Day{Part1: day11.Part1, Part2: day11.Part2})
+		return
 	}
 
-	return monkeys
-}
-
-func parseMonkey(input string) Monkey {
-	lines := strings.Split(input, "\n")
-	// structure of monkey notes:
-	// Monkey [n]:
-	//   Starting items: [...]
-	//   Operation: [...]
-	//   Test: divisible by [n]
-	//     If true: throw to monkey [n]
-	//     If false: throw to monkey [n]
-
-	if lines[0][:6] != "Monkey" {
-		panic(errors.New("error in input"))
+	// Part 1's relief stops items from repeating, so --fast only runs part 1
+	// if it is told to go without relief
+	noRelief := *relief == 1 || (*relief == 0 && *part != 1)
+	if flag.NArg() != 1 || *part < 0 || *part > 2 || rounds.Sign() < 0 || moreThanOne(*exact, *verify, *fast) || (*trace != "" && (*part == 0 || *fast)) || (*fast && !noRelief) {
+		flag.Usage()
+		os.Exit(2)
 	}
-
-	items := parseItems(lines[1])
-	operation := parseOperation(lines[2])
-	test := parseTest(lines[3:])
-
-	return Monkey{
-		items:       items,
-		operation:   operation,
-		test:        test,
-		inspections: 0,
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	} else if *fast {
+		parts = []int{2}
 	}
-}
-
-func parseItems(itemsStr string) (items []int) {
-	for _, itemStr := range strings.Split(itemsStr[18:], ", ") {
-		item, _ := strconv.Atoi(itemStr)
-		items = append(items, item)
+	o := options{relief: *relief, exact: *exact, verify: *verify, fast: *fast, trace: *trace}
+	if rounds.Sign() > 0 {
+		o.rounds = rounds
 	}
-
-	return
-}
-
-func parseOperation(operationStr string) Operation {
-	tokens := strings.Split(operationStr[13:], " ")
-	var leftInt, rightInt int
-	leftInt, _ = strconv.Atoi(tokens[2])
-	rightInt, _ = strconv.Atoi(tokens[4])
-
-	return Operation{
-		operator: tokens[3],
-		left:     tokens[2],
-		right:    tokens[4],
-		leftInt:  leftInt,
-		rightInt: rightInt,
+	if err := run(flag.Arg(0), parts, o); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
 
-func parseTest(lines []string) Test {
-	testStr := lines[0][8:]
-	testTrueStr := lines[1][13:]
-	testFalseStr := lines[2][14:]
-
-	divisor, _ := strconv.Atoi(testStr[13:])
-	ifTrue, _ := strconv.Atoi(testTrueStr[16:])
-	ifFalse, _ := strconv.Atoi(testFalseStr[16:])
-	return Test{
-		condition: divisor,
-		trueCase:  ifTrue,
-		falseCase: ifFalse,
+func run(path string, parts []int, o options) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	monkeys, err := day11.ReadMonkeys(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
 	}
-}
 
-func doRound(monkeys *[]Monkey) {
-	modulo := calculateMonkeyModulo(*monkeys)
+	for _, part := range parts {
+		rounds, relief := big.NewInt(day11.PART1_ROUNDS), day11.PART1_RELIEF
+		if part == 2 {
+			rounds, relief = big.NewInt(day11.PART2_ROUNDS), day11.PART2_RELIEF
+		}
+		if o.rounds != nil {
+			rounds = o.rounds
+		}
+		if o.relief != 0 {
+			relief = o.relief
+		}
 
-	for i, monkey := range *monkeys {
-		for _, item := range monkey.items {
-			newWorry := (doOperation(monkey.operation, item) / WORRY_DIVISOR) % modulo
-			modWorry := newWorry % monkey.test.condition
-			if modWorry == 0 {
-				(*monkeys)[monkey.test.trueCase].items = append((*monkeys)[monkey.test.trueCase].items, newWorry)
-			} else {
-				(*monkeys)[monkey.test.falseCase].items = append((*monkeys)[monkey.test.falseCase].items, newWorry)
+		if o.fast {
+			inspections, err := day11.Extrapolate(monkeys, rounds)
+			if err != nil {
+				return fmt.Errorf("part %d: %w", part, err)
 			}
-			(*monkeys)[i].inspections++
+			fmt.Printf("part %d: %s\n", part, day11.BigMonkeyBusiness(inspections))
+			continue
 		}
 
-		(*monkeys)[i].items = []int{}
-	}
-}
-
-func printMonkeys(monkeys *[]Monkey) {
-	for i, monkey := range *monkeys {
-		fmt.Printf("Monkey %d: ", i)
-		for _, item := range monkey.items {
-			fmt.Printf("%d, ", item)
+		if !rounds.IsInt64() || rounds.Int64() > math.MaxInt {
+			return fmt.Errorf("part %d: %s rounds are too many to play (try --fast)", part, rounds)
+		}
+		monkeyBusiness, err := simulate(monkeys, int(rounds.Int64()), relief, o)
+		if err != nil {
+			return fmt.Errorf("part %d: %w", part, err)
 		}
-		fmt.Printf("\n")
+		fmt.Printf("part %d: %d\n", part, monkeyBusiness)
 	}
+	return nil
 }
 
-func doOperation(op Operation, oldValue int) (newValue int) {
-	var left, right int
-
-	if op.left == "old" {
-		left = oldValue
-	} else {
-		left = op.leftInt
+func simulate(monkeys []day11.Monkey, rounds int, relief int, o options) (int, error) {
+	if o.verify {
+		return day11.Verify(monkeys, rounds, relief)
 	}
 
-	if op.right == "old" {
-		right = oldValue
-	} else {
-		right = op.rightInt
+	s, err := day11.NewSimulation(monkeys, relief, o.exact)
+	if err != nil {
+		return 0, err
 	}
-
-	switch op.operator {
-	case "+":
-		{
-			newValue = left + right
-		}
-	case "-":
-		{
-			newValue = left - right
-		}
-	case "*":
-		{
-			newValue = left * right
-		}
-	case "/":
-		{
-			newValue = left / right
-		}
-	default:
-		{
-			panic(errors.New("unknown operator"))
-		}
+	if o.trace == "" {
+		err = s.Run(rounds, nil)
+		return s.MonkeyBusiness(), err
 	}
 
-	return
-}
-
-func calculateMonkeyBusiness(monkeys []Monkey) int {
-	sort.Slice(monkeys, func(i, j int) bool {
-		return monkeys[i].inspections < monkeys[j].inspections
-	})
-
-	return monkeys[len(monkeys)-1].inspections * monkeys[len(monkeys)-2].inspections
+	out, err := os.Create(o.trace)
+	if err != nil {
+		return 0, err
+	}
+	trace := day11.NewTraceWriter(out)
+	err = s.Run(rounds, trace.Record)
+	if flushErr := trace.Flush(); err == nil {
+		err = flushErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return s.MonkeyBusiness(), err
 }
 
-func calculateMonkeyModulo(monkeys []Monkey) int {
-	product := 1
-	for _, monkey := range monkeys {
-		product *= monkey.test.condition
+func moreThanOne(flags ...bool) bool {
+	set := 0
+	for _, f := range flags {
+		if f {
+			set++
+		}
 	}
-
-	return product
+	return set > 1
 }
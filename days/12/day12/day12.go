@@ -0,0 +1,151 @@
+package day12
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+	"github.com/FaideWW/aoc-2022/search"
+)
+
+type Grid struct {
+	start    grid.Vec2
+	end      grid.Vec2
+	tiles    *grid.Dense[int]
+	lowTiles []grid.Vec2
+}
+
+// Part1 returns the length of the shortest path from the start to the end
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	g, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	path, ok := g.findShortestPath([]grid.Vec2{g.start}, g.end)
+	if !ok {
+		return "", errors.New("no path from the start to the end")
+	}
+
+	return fmt.Sprint(len(path) - 1), nil
+}
+
+// Part2 returns the length of the shortest path from any of the lowest tiles
+// to the end
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	g, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+
+	// searching from every low tile at once finds whichever is closest
+	path, ok := g.findShortestPath(g.lowTiles, g.end)
+	if !ok {
+		return "", errors.New("no path from a low tile to the end")
+	}
+
+	return fmt.Sprint(len(path) - 1), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) (Grid, error) {
+	var start grid.Vec2
+	var end grid.Vec2
+	starts := 0
+	ends := 0
+	lowTiles := make([]grid.Vec2, 0)
+
+	tiles, err := grid.ParseStrict(input, func(pos grid.Vec2, tile byte) (int, error) {
+		if tile == 'S' {
+			start = pos
+			starts++
+		}
+		if tile == 'E' {
+			end = pos
+			ends++
+		}
+		height, ok := runeToHeight(rune(tile))
+		if !ok {
+			return 0, fmt.Errorf("invalid height %q", tile)
+		}
+		if height == 0 {
+			lowTiles = append(lowTiles, pos)
+		}
+		return height, nil
+	})
+	if err != nil {
+		return Grid{}, err
+	}
+	if starts != 1 || ends != 1 {
+		return Grid{}, fmt.Errorf("expected exactly one start (S) and one end (E), got %d and %d", starts, ends)
+	}
+
+	return Grid{
+		start:    start,
+		end:      end,
+		tiles:    tiles,
+		lowTiles: lowTiles,
+	}, nil
+}
+
+func runeToHeight(r rune) (int, bool) {
+	if r == 'S' {
+		return 0, true
+	}
+	if r == 'E' {
+		return 25, true
+	}
+	if r < 'a' || r > 'z' {
+		return 0, false
+	}
+	return int(r - 'a'), true
+}
+
+// findShortestPath returns the shortest path from any of the starts to the
+// end, including both
+func (g *Grid) findShortestPath(starts []grid.Vec2, end grid.Vec2) ([]grid.Vec2, bool) {
+	graph := search.Unweighted[grid.Vec2](g.getNeighbors)
+	result := search.BFS[grid.Vec2](graph, starts, func(pos grid.Vec2) bool {
+		return pos == end
+	})
+	if !result.Found {
+		return nil, false
+	}
+
+	return result.Path(end), true
+}
+
+func (g *Grid) getNeighbors(pos grid.Vec2) []grid.Vec2 {
+	neighbors := make([]grid.Vec2, 0)
+	for _, neighbor := range g.tiles.Neighbours4(pos) {
+		if g.isReachable(pos, neighbor) {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+
+	return neighbors
+}
+
+func (g *Grid) isReachable(a grid.Vec2, b grid.Vec2) bool {
+	aHeight := g.tiles.At(a)
+	bHeight := g.tiles.At(b)
+
+	if bHeight-aHeight > 1 {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,257 @@
+package day13
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+type Integer int
+type List []Datum
+
+type Datum interface {
+	compare(d Datum) int
+}
+
+type Packet Datum
+
+type PacketPair struct {
+	left  Packet
+	right Packet
+}
+
+// Part1 returns the sum of the indices of the pairs that are in the right
+// order
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	packetPairs, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+
+	sumIndices := 0
+	for i, pair := range packetPairs {
+		if pair.isInOrder() {
+			sumIndices += i + 1
+		}
+	}
+
+	return fmt.Sprint(sumIndices), nil
+}
+
+// Part2 returns the decoder key for the distress signal
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	packetPairs, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	packets := flattenPairs(packetPairs)
+
+	dividers := []Packet{List{List{Integer(2)}}, List{List{Integer(6)}}}
+
+	packets = append(packets, dividers...)
+
+	sort.Slice(packets, func(i, j int) bool {
+		return packets[i].compare(packets[j]) < 0
+	})
+
+	divider1Index := findIndex(packets, dividers[0]) + 1
+	divider2Index := findIndex(packets, dividers[1]) + 1
+
+	return fmt.Sprint(divider1Index * divider2Index), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) ([]PacketPair, error) {
+	inputPairs := parse.Paragraphs(input)
+	pairs := make([]PacketPair, len(inputPairs))
+
+	for i, inputPackets := range inputPairs {
+		if len(inputPackets) != 2 {
+			return nil, inputPackets[0].Errorf("expected a pair of packets, got %d", len(inputPackets))
+		}
+		left, err := parsePacket(inputPackets[0])
+		if err != nil {
+			return nil, err
+		}
+		right, err := parsePacket(inputPackets[1])
+		if err != nil {
+			return nil, err
+		}
+		pairs[i] = PacketPair{
+			left:  left,
+			right: right,
+		}
+	}
+
+	return pairs, nil
+}
+
+// packetParser reads a packet one character at a time, keeping track of its
+// position for error messages
+type packetParser struct {
+	input parse.Field
+	pos   int
+}
+
+func parsePacket(input parse.Field) (Packet, error) {
+	p := packetParser{input: input}
+	list, err := p.parseList()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(input.Text) {
+		return nil, p.errorf("unexpected %q after the end of the packet", input.Text[p.pos])
+	}
+	return list, nil
+}
+
+func (p *packetParser) errorf(format string, a ...any) error {
+	return parse.Errorf(p.input.Line, p.input.Col+p.pos, format, a...)
+}
+
+// peek returns the next character, or 0 at the end of the input
+func (p *packetParser) peek() byte {
+	if p.pos >= len(p.input.Text) {
+		return 0
+	}
+	return p.input.Text[p.pos]
+}
+
+func (p *packetParser) parseList() (List, error) {
+	if p.peek() != '[' {
+		return nil, p.errorf("expected '['")
+	}
+	p.pos++
+
+	list := make(List, 0)
+	if p.peek() == ']' {
+		p.pos++
+		return list, nil
+	}
+
+	for {
+		datum, err := p.parseDatum()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, datum)
+
+		switch p.peek() {
+		case ',':
+			{
+				p.pos++
+			}
+		case ']':
+			{
+				p.pos++
+				return list, nil
+			}
+		case 0:
+			{
+				return nil, p.errorf("unexpected end of packet")
+			}
+		default:
+			{
+				return nil, p.errorf("expected ',' or ']', got %q", p.peek())
+			}
+		}
+	}
+}
+
+func (p *packetParser) parseDatum() (Datum, error) {
+	if p.peek() == '[' {
+		return p.parseList()
+	}
+
+	start := p.pos
+	for p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.peek() == 0 {
+			return nil, p.errorf("unexpected end of packet")
+		}
+		return nil, p.errorf("expected a number or a list, got %q", p.peek())
+	}
+
+	n, err := strconv.Atoi(p.input.Text[start:p.pos])
+	if err != nil {
+		return nil, parse.Errorf(p.input.Line, p.input.Col+start, "invalid number %q", p.input.Text[start:p.pos])
+	}
+	return Integer(n), nil
+}
+
+func (i Integer) compare(d Datum) int {
+	// if d is an int, compare the two ints
+	if dInt, ok := d.(Integer); ok {
+		return (int)(i - dInt)
+	}
+
+	// if d is a list, up-convert i to a list
+	iList := List{i}
+	return iList.compare(d)
+}
+
+func (l List) compare(d Datum) int {
+	var dList List
+	// if d is an int, up-convert d to a list
+	if dInt, ok := d.(Integer); ok {
+		dList = List{dInt}
+	} else {
+		// if d is a list, compare the two lists
+		dList = d.(List)
+	}
+
+	var minLen int
+	if len(l) < len(dList) {
+		minLen = len(l)
+	} else {
+		minLen = len(dList)
+	}
+
+	for i := 0; i < minLen; i++ {
+		result := l[i].compare(dList[i])
+		if result != 0 {
+			return result
+		}
+	}
+	return len(l) - len(dList)
+}
+
+func (p PacketPair) isInOrder() bool {
+	return p.left.compare(p.right) <= 0
+}
+
+func flattenPairs(pairs []PacketPair) []Packet {
+	packets := make([]Packet, len(pairs)*2)
+	for i, pair := range pairs {
+		packets[2*i] = pair.left
+		packets[2*i+1] = pair.right
+	}
+	return packets
+}
+
+func findIndex(packets []Packet, toFind Packet) int {
+	for i, toCompare := range packets {
+		if toCompare.compare(toFind) == 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,255 @@
+package day14
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+type Cavern struct {
+	rocks      grid.Sparse[bool]
+	sand       grid.Sparse[bool]
+	bounds     grid.Box
+	floorDepth int
+	hasFloor   bool
+}
+
+const SAND_ORIGIN_X = 500
+const SAND_ORIGIN_Y = 0
+
+// Part1 returns the number of grains of sand that settle before sand starts
+// falling into the abyss
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	cavern, err := parseCavern(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	cavern.hasFloor = false
+	return fmt.Sprint(cavern.fill()), nil
+}
+
+// Part2 returns the number of grains of sand that settle before the source is
+// blocked, when there is a floor beneath the cavern
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	cavern, err := parseCavern(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(cavern.fill()), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+// Produce sand until it no longer settles, returning the number of grains that
+// settled
+func (c *Cavern) fill() int {
+	sandCount := -1
+	settled := true
+	for settled {
+		sandCount++
+		settled = c.produceSand()
+	}
+
+	return sandCount
+}
+
+func parseCavern(input string) (Cavern, error) {
+	lines := parse.Lines(input)
+	rocks := make(grid.Sparse[bool])
+
+	cavern := Cavern{
+		bounds: grid.EmptyBox(),
+		sand:   make(grid.Sparse[bool]),
+	}
+
+	for _, line := range lines {
+		vertices := line.Split(" -> ")
+		lastVertex, err := parsePosition(vertices[0])
+		if err != nil {
+			return Cavern{}, err
+		}
+		rocks[lastVertex] = true
+		cavern.bounds = cavern.bounds.Extend(lastVertex)
+		for i := 1; i < len(vertices); i++ {
+			currentVertex, err := parsePosition(vertices[i])
+			if err != nil {
+				return Cavern{}, err
+			}
+			if currentVertex.X != lastVertex.X && currentVertex.Y != lastVertex.Y {
+				return Cavern{}, vertices[i].Errorf("rock paths must be horizontal or vertical")
+			}
+			cavern.bounds = cavern.bounds.Extend(currentVertex)
+			for _, rock := range makeRockRun(lastVertex, currentVertex) {
+				rocks[rock] = true
+			}
+
+			lastVertex = currentVertex
+		}
+	}
+
+	cavern.rocks = rocks
+	cavern.floorDepth = cavern.bounds.Max.Y + 2
+	cavern.hasFloor = true
+
+	return cavern, nil
+}
+
+func parsePosition(input parse.Field) (grid.Vec2, error) {
+	coords, err := input.SplitN(",", 2, "a position like \"498,4\"")
+	if err != nil {
+		return grid.Vec2{}, err
+	}
+	xy, err := parse.Ints(coords)
+	if err != nil {
+		return grid.Vec2{}, err
+	}
+	if xy[1] < SAND_ORIGIN_Y {
+		return grid.Vec2{}, coords[1].Errorf("rock above the sand source")
+	}
+
+	return grid.Vec2{X: xy[0], Y: xy[1]}, nil
+}
+
+func makeRockRun(from grid.Vec2, to grid.Vec2) []grid.Vec2 {
+	// Runs are always either horizontal or vertical
+	delta := to.Sub(from)
+	direction := delta.Sign()
+
+	rocks := make([]grid.Vec2, delta.Manhattan())
+	for i := 0; i < len(rocks); i++ {
+		rocks[i] = from.Add(direction.Times(i + 1))
+	}
+
+	return rocks
+}
+
+func (c *Cavern) hasRock(p grid.Vec2) bool {
+	if c.hasFloor && p.Y == c.floorDepth {
+		return true
+	}
+
+	return c.rocks.Has(p)
+}
+
+func (c *Cavern) hasSand(p grid.Vec2) bool {
+	return c.sand.Has(p)
+}
+
+func (c *Cavern) findNextObstacleDown(p grid.Vec2) (grid.Vec2, bool) {
+	for y := 0; y <= c.bounds.Max.Y; y++ {
+		nextPosition := p.Add(grid.Vec2{X: 0, Y: y + 1})
+		if c.hasRock(nextPosition) || c.hasSand(nextPosition) {
+			return nextPosition, true
+		}
+	}
+
+	// If no obstacles are found, return ok=false
+	return p, false
+}
+
+func createSand() grid.Vec2 {
+	return grid.Vec2{X: SAND_ORIGIN_X, Y: SAND_ORIGIN_Y}
+}
+
+// Create a sand particle and calculate where it settles. Returns true if the
+// sand was able to settle, or false if the sand fell into the abyss (part 1)
+// or was blocked at the source (part 2)
+func (c *Cavern) produceSand() bool {
+	sand := createSand()
+	if c.hasRock(sand) || c.hasSand(sand) {
+		return false
+	}
+
+	settled := false
+	for !settled {
+
+		obstacle, ok := c.findNextObstacleDown(sand)
+		if !ok {
+			return false
+		}
+
+		left := obstacle.Add(grid.Vec2{X: -1, Y: 0})
+		right := obstacle.Add(grid.Vec2{X: 1, Y: 0})
+		if !c.hasRock(left) && !c.hasSand(left) {
+			// check left of the obstacle
+			sand = left
+		} else if !c.hasRock(right) && !c.hasSand(right) {
+			// check right of the obstacle
+			sand = right
+		} else {
+			settledAt := obstacle.Add(grid.Vec2{X: 0, Y: -1})
+			c.sand[settledAt] = true
+			settled = true
+		}
+	}
+
+	return true
+}
+
+func (c *Cavern) print() {
+	// print headers. assume all headers are 3 digits at most
+	depthAxisLength := len(fmt.Sprint(c.bounds.Max.Y)) + 1
+	fmt.Fprintln(solver.DebugOutput)
+	for y := 0; y < 3; y++ {
+		var line string
+		for x := c.bounds.Min.X - depthAxisLength; x < c.bounds.Max.X+1; x++ {
+			switch x {
+			case c.bounds.Min.X:
+				{
+					line += string(fmt.Sprint(c.bounds.Min.X)[y])
+				}
+			case c.bounds.Max.X:
+				{
+					line += string(fmt.Sprint(c.bounds.Max.X)[y])
+
+				}
+			case SAND_ORIGIN_X:
+				{
+					line += string(fmt.Sprint(SAND_ORIGIN_X)[y])
+				}
+			default:
+				{
+					line += " "
+				}
+			}
+		}
+		fmt.Fprintln(solver.DebugOutput, line)
+	}
+
+	for y := 0; y < c.floorDepth+1; y++ {
+		line := fmt.Sprintf("%d", y)
+		currentDepthSize := len(fmt.Sprint(y))
+		for i := 0; i < depthAxisLength-currentDepthSize; i++ {
+			line += " "
+		}
+
+		for x := c.bounds.Min.X; x < c.bounds.Max.X+1; x++ {
+			pos := grid.Vec2{X: x, Y: y}
+			if x == SAND_ORIGIN_X && y == SAND_ORIGIN_Y {
+				line += "+"
+			} else if c.hasRock(pos) {
+				line += "#"
+			} else if c.hasSand(pos) {
+				line += "o"
+			} else {
+				line += "."
+			}
+		}
+		fmt.Fprintln(solver.DebugOutput, line)
+	}
+}
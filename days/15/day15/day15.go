@@ -0,0 +1,276 @@
+package day15
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+	"github.com/FaideWW/aoc-2022/interval"
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+type Sensor struct {
+	pos    grid.Vec2
+	radius int
+}
+
+type Cavern struct {
+	sensors map[grid.Vec2]Sensor
+	beacons map[grid.Vec2]bool
+	bounds  grid.Box
+}
+
+const Y_LEVEL = 2000000
+const SEARCH_AREA = 4000000
+const TUNING_CONSTANT = 4000000
+
+const EXAMPLE_Y_LEVEL = 10
+const EXAMPLE_SEARCH_AREA = 20
+
+// Part1 returns the number of positions on one row that cannot contain a
+// beacon
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	cavern, err := parseCavern(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	yLevel, _ := cavern.searchParameters()
+	return fmt.Sprint(cavern.findLevelCoverage(yLevel)), nil
+}
+
+// Part2 returns the tuning frequency of the missing beacon
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	cavern, err := parseCavern(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	_, searchArea := cavern.searchParameters()
+
+	beacon, err := cavern.findMissingBeacon(searchArea)
+	if err != nil {
+		return "", err
+	}
+	tuningFreq := beacon.X*TUNING_CONSTANT + beacon.Y
+	return fmt.Sprint(tuningFreq), nil
+}
+
+// The example input uses a much smaller search area than the real input, so
+// detect it by the size of the cavern
+func (c *Cavern) searchParameters() (yLevel int, searchArea int) {
+	if c.bounds.Max.X < EXAMPLE_SEARCH_AREA*2 {
+		return EXAMPLE_Y_LEVEL, EXAMPLE_SEARCH_AREA
+	}
+	return Y_LEVEL, SEARCH_AREA
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseCavern(input string) (Cavern, error) {
+	r := regexp.MustCompile(`Sensor at x=(-?\d+), y=(-?\d+): closest beacon is at x=(-?\d+), y=(-?\d+)`)
+
+	lines := parse.Lines(input)
+	sensors := make(map[grid.Vec2]Sensor)
+	beacons := make(map[grid.Vec2]bool)
+
+	bounds := grid.EmptyBox()
+
+	for _, line := range lines {
+		data, err := line.Match(r, "\"Sensor at x=<x>, y=<y>: closest beacon is at x=<x>, y=<y>\"")
+		if err != nil {
+			return Cavern{}, err
+		}
+		coords, err := parse.Ints(data)
+		if err != nil {
+			return Cavern{}, err
+		}
+
+		sensorPos := grid.Vec2{X: coords[0], Y: coords[1]}
+		beaconPos := grid.Vec2{X: coords[2], Y: coords[3]}
+		if _, ok := sensors[sensorPos]; ok {
+			return Cavern{}, line.Errorf("duplicate sensor at %d,%d", sensorPos.X, sensorPos.Y)
+		}
+		bounds = bounds.Extend(sensorPos).Extend(beaconPos)
+
+		sensors[sensorPos] = Sensor{
+			pos:    sensorPos,
+			radius: calculateManhattanDistance(sensorPos, beaconPos),
+		}
+
+		if !beacons[beaconPos] {
+			beacons[beaconPos] = true
+		}
+	}
+
+	return Cavern{
+		sensors: sensors,
+		beacons: beacons,
+		bounds:  bounds,
+	}, nil
+}
+
+func calculateManhattanDistance(a grid.Vec2, b grid.Vec2) int {
+	return a.Sub(b).Manhattan()
+}
+
+func (c *Cavern) hasSensor(pos grid.Vec2) bool {
+	_, ok := c.sensors[pos]
+	return ok
+}
+
+func (c *Cavern) hasBeacon(pos grid.Vec2) bool {
+	return c.beacons[pos]
+}
+
+// coverageAt returns the x coordinates on row y that are within range of a
+// sensor
+func (c *Cavern) coverageAt(y int) *interval.Set {
+	coverage := interval.NewSet()
+	for _, sensor := range c.sensors {
+		coverage.Insert(sensor.rowCoverage(y))
+	}
+	return coverage
+}
+
+// rowCoverage returns the x coordinates on row y within the sensor's range
+func (s *Sensor) rowCoverage(y int) interval.Interval {
+	dy := y - s.pos.Y
+	if dy < 0 {
+		dy *= -1
+	}
+	xRange := s.radius - dy
+	return interval.Interval{Min: s.pos.X - xRange, Max: s.pos.X + xRange}
+}
+
+func (c *Cavern) findLevelCoverage(y int) (coveredTiles int) {
+	coverage := c.coverageAt(y)
+	coveredTiles = coverage.Len()
+
+	// Known beacons don't count, since a beacon can clearly be there
+	for beacon := range c.beacons {
+		if beacon.Y == y && coverage.Contains(beacon.X) {
+			coveredTiles--
+		}
+	}
+
+	return
+}
+
+func (s *Sensor) isInRadius(pos grid.Vec2) bool {
+	return calculateManhattanDistance(s.pos, pos) <= s.radius
+}
+
+// findMissingBeacon finds the only position within the search area that no
+// sensor can see, by covering each row with the sensors' ranges and keeping
+// track of the rows that have been filled
+func (c *Cavern) findMissingBeacon(maxCoord int) (grid.Vec2, error) {
+	searchArea := interval.Interval{Min: 0, Max: maxCoord}
+	fullLevels := interval.NewSet()
+	levels := make(map[int]*interval.Set, 0)
+
+	for _, sensor := range c.sensors {
+		for y := sensor.pos.Y - sensor.radius; y <= sensor.pos.Y+sensor.radius; y++ {
+			if y < 0 || y > maxCoord {
+				continue
+			}
+			if fullLevels.Contains(y) {
+				continue
+			}
+			_, ok := levels[y]
+			if !ok {
+				levels[y] = interval.NewSet()
+			}
+			levels[y].Insert(sensor.rowCoverage(y).Intersect(searchArea))
+
+			if levels[y].Covers(searchArea) {
+				fullLevels.Insert(interval.Interval{Min: y, Max: y})
+				delete(levels, y)
+			}
+		}
+	}
+
+	// Rows no sensor reaches at all are completely open
+	openRows := fullLevels.Complement(searchArea)
+	for _, rows := range openRows.Intervals() {
+		for y := rows.Min; y <= rows.Max; y++ {
+			if _, ok := levels[y]; !ok {
+				levels[y] = interval.NewSet()
+			}
+		}
+	}
+
+	candidates := make([]grid.Vec2, 0)
+	for y, level := range levels {
+		open := level.Complement(searchArea)
+		if open.Len() > 1 {
+			return grid.Vec2{}, fmt.Errorf("%d positions on row %d could hold the missing beacon", open.Len(), y)
+		}
+		for _, x := range open.Intervals() {
+			candidates = append(candidates, grid.Vec2{X: x.Min, Y: y})
+		}
+	}
+	if len(candidates) != 1 {
+		return grid.Vec2{}, fmt.Errorf("found %d candidate positions for the missing beacon, expected exactly 1", len(candidates))
+	}
+
+	return candidates[0], nil
+}
+
+func (c *Cavern) print() string {
+	var printout string
+	// print headers. assume all headers are 3 digits at most
+	rangeAxisHeight := len(fmt.Sprint(c.bounds.Max.X)) + 1
+	depthAxisLength := len(fmt.Sprint(c.bounds.Max.Y)) + 1
+	for y := 0; y < rangeAxisHeight; y++ {
+		var line string
+		for x := c.bounds.Min.X - (depthAxisLength + 1); x < c.bounds.Max.X+1; x++ {
+			xStr := fmt.Sprint(x)
+			if x >= 0 && x%5 == 0 {
+				digit := len(xStr) - (rangeAxisHeight - y)
+				if digit >= 0 {
+					line += string(xStr[digit])
+				} else {
+					line += " "
+				}
+			} else {
+				line += " "
+			}
+		}
+		printout += fmt.Sprintln(line)
+	}
+
+	for y := 0; y < c.bounds.Max.Y+1; y++ {
+		var line string
+		currentDepthSize := len(fmt.Sprint(y))
+		for i := 0; i < depthAxisLength-currentDepthSize; i++ {
+			line += " "
+		}
+		line += fmt.Sprintf("%d ", y)
+
+		for x := c.bounds.Min.X; x < c.bounds.Max.X+1; x++ {
+			pos := grid.Vec2{X: x, Y: y}
+			if c.hasSensor(pos) {
+				line += "S"
+			} else if c.hasBeacon(pos) {
+				line += "B"
+			} else {
+				line += "."
+			}
+		}
+		printout += fmt.Sprintln(line)
+	}
+
+	return printout
+}
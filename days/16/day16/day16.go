@@ -0,0 +1,273 @@
+package day16
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/search"
+)
+
+type Cavern struct {
+	valves            []string
+	usefulValves      []string
+	valveFlowRates    map[string]int
+	tunnelConnections map[string][]string
+	distanceMatrix    map[string]map[string]int
+}
+
+type PressureEvent struct {
+	state    string
+	pressure int
+}
+
+const STARTING_LOCATION = "AA"
+const SOLO_MINUTES = 30
+const PAIR_MINUTES = 26
+
+// Part1 returns the most pressure that can be released alone in 30 minutes
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	cavern, err := ParseCavern(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	cavern.ComputeDistanceMatrix()
+	return fmt.Sprint(cavern.FindMaxPressure(STARTING_LOCATION, SOLO_MINUTES)), nil
+}
+
+// Part2 returns the most pressure that can be released with the help of an
+// elephant in 26 minutes
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	cavern, err := ParseCavern(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	cavern.ComputeDistanceMatrix()
+	return fmt.Sprint(cavern.FindMaxPairPressure(STARTING_LOCATION, PAIR_MINUTES)), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+// ParseCavern reads the valves and the tunnels between them from the scan
+func ParseCavern(input string) (Cavern, error) {
+	lines := parse.Lines(input)
+
+	valveFlowRates := make(map[string]int)
+	tunnelConnections := make(map[string][]string)
+	closedValves := make([]string, 0)
+	usefulValves := make([]string, 0)
+	tunnelFields := make([][]parse.Field, 0)
+
+	r := regexp.MustCompile(`Valve ([A-Z]{2}) has flow rate=(\d+); tunnels? leads? to valves? (.+)`)
+	for _, line := range lines {
+		matches, err := line.Match(r, "\"Valve <name> has flow rate=<n>; tunnels lead to valves <names>\"")
+		if err != nil {
+			return Cavern{}, err
+		}
+
+		currentValve := matches[0].Text
+		if _, ok := valveFlowRates[currentValve]; ok {
+			return Cavern{}, matches[0].Errorf("valve %s is described twice", currentValve)
+		}
+		flowRate, err := matches[1].Int()
+		if err != nil {
+			return Cavern{}, err
+		}
+		connections := matches[2].Split(", ")
+		tunnelFields = append(tunnelFields, connections)
+
+		closedValves = append(closedValves, currentValve)
+		if flowRate > 0 {
+			usefulValves = append(usefulValves, currentValve)
+		}
+		valveFlowRates[currentValve] = flowRate
+		for _, connection := range connections {
+			tunnelConnections[currentValve] = append(tunnelConnections[currentValve], connection.Text)
+		}
+	}
+
+	// tunnels can lead to valves described further down the scan
+	for _, connections := range tunnelFields {
+		for _, connection := range connections {
+			if _, ok := valveFlowRates[connection.Text]; !ok {
+				return Cavern{}, connection.Errorf("tunnel to unknown valve %q", connection.Text)
+			}
+		}
+	}
+	if _, ok := valveFlowRates[STARTING_LOCATION]; !ok {
+		return Cavern{}, fmt.Errorf("no starting valve %s", STARTING_LOCATION)
+	}
+
+	cavern := Cavern{
+		valves:            closedValves,
+		usefulValves:      usefulValves,
+		valveFlowRates:    valveFlowRates,
+		tunnelConnections: tunnelConnections,
+	}
+	return cavern, nil
+}
+
+// ComputeDistanceMatrix finds the shortest distance between every pair of
+// valves. It must be called before searching for the maximum pressure
+func (c *Cavern) ComputeDistanceMatrix() {
+	tunnels := search.Unweighted[string](func(valve string) []string {
+		return c.tunnelConnections[valve]
+	})
+	c.distanceMatrix = search.AllPairs[string](tunnels, c.valves)
+}
+
+func sortedAppend(array []string, item string) []string {
+	insertAt := -1
+	for i := 0; i < len(array); i++ {
+		if array[i] < item {
+			continue
+		} else if array[i] >= item {
+			insertAt = i
+			break
+		}
+	}
+
+	if insertAt == -1 {
+		return append(array, item)
+	}
+	res := append(array[:insertAt+1], array[insertAt:]...)
+	res[insertAt] = item
+	return res
+}
+
+// Assume all valves are sorted alphabetically already so the order is guaranteed
+func serializeValveState(position string, valves map[string]bool) string {
+	sortedValves := make([]string, 0)
+	for valve := range valves {
+		sortedValves = sortedAppend(sortedValves, valve)
+	}
+	return position + ":" + strings.Join(sortedValves, ",")
+}
+
+// Memoize every reachable set of open valves, and return the best pressure
+// released by each of them at the time limit in descending order
+func (c *Cavern) findFinalPressures(startingLocation string, timeLimit int) []PressureEvent {
+	memo := make(map[int]map[string]int)
+	for i := 0; i < timeLimit; i++ {
+		memo[i] = make(map[string]int)
+	}
+
+	var compute func(int, string, map[string]bool)
+	compute = func(timeTaken int, position string, openValves map[string]bool) {
+		valveState := serializeValveState(position, openValves)
+		for _, nextValve := range c.usefulValves {
+			// if the valve is already open, skip it
+			if openValves[nextValve] {
+				continue
+			}
+
+			// if opening this valve will take too long, then we can memoize the
+			// value at the time limit to be equal to now (since there's nothing
+			// to be gained by moving)
+			timeToOpen := timeTaken + c.distanceMatrix[position][nextValve] + 1
+			if timeToOpen >= timeLimit {
+				if memo[timeLimit-1][valveState] < memo[timeTaken][valveState] {
+					memo[timeLimit-1][valveState] = memo[timeTaken][valveState]
+				}
+			} else {
+				additionalPressure := (timeLimit - timeToOpen) * c.valveFlowRates[nextValve]
+				nextOpenValves := make(map[string]bool)
+				for k, v := range openValves {
+					nextOpenValves[k] = v
+				}
+
+				nextOpenValves[nextValve] = true
+				nextValveState := serializeValveState(nextValve, nextOpenValves)
+
+				nextPressure := additionalPressure + memo[timeTaken][valveState]
+
+				if memo[timeToOpen][nextValveState] < nextPressure {
+					memo[timeToOpen][nextValveState] = nextPressure
+				}
+
+				compute(timeToOpen, nextValve, nextOpenValves)
+			}
+
+		}
+		// Additionally, if we've iterated over all the valves, memoize this pressure at the time limit to simulate waiting at this step
+		if memo[timeLimit-1][valveState] < memo[timeTaken][valveState] {
+			memo[timeLimit-1][valveState] = memo[timeTaken][valveState]
+		}
+	}
+
+	// fill in the memo table with all possible permutations of valve openings
+	compute(0, startingLocation, make(map[string]bool))
+
+	// find the permutations at time=timeLimit with the highest pressure
+	finalPressures := make([]PressureEvent, 0)
+	for k, v := range memo[timeLimit-1] {
+		finalPressures = append(finalPressures, PressureEvent{k, v})
+	}
+
+	sort.Slice(finalPressures, func(i, j int) bool {
+		return finalPressures[i].pressure > finalPressures[j].pressure
+	})
+
+	return finalPressures
+}
+
+// FindMaxPressure returns the most pressure a single actor can release
+func (c *Cavern) FindMaxPressure(startingLocation string, timeLimit int) int {
+	return c.findFinalPressures(startingLocation, timeLimit)[0].pressure
+}
+
+// FindMaxPairPressure returns the most pressure two actors can release by
+// opening disjoint sets of valves
+func (c *Cavern) FindMaxPairPressure(startingLocation string, timeLimit int) int {
+	finalPressures := c.findFinalPressures(startingLocation, timeLimit)
+
+	// now that we've memoized all states, find the two ending states where two actors open unique sets of valves that add up to the highest combined total
+	maxPressure := 0
+	for i := 0; i < len(finalPressures); i++ {
+		firstPressure := finalPressures[i]
+		for j := i + 1; j < len(finalPressures); j++ {
+			secondPressure := finalPressures[j]
+			if !doStatesOverlap(firstPressure.state, secondPressure.state) {
+				sum := firstPressure.pressure + secondPressure.pressure
+				if sum > maxPressure {
+					maxPressure = sum
+				}
+				break
+			}
+		}
+	}
+
+	return maxPressure
+}
+
+func doStatesOverlap(state1 string, state2 string) bool {
+	parts1 := strings.Split(strings.Split(state1, ":")[1], ",")
+	parts2 := strings.Split(strings.Split(state2, ":")[1], ",")
+
+	seen := make(map[string]bool)
+	for _, p := range parts1 {
+		seen[p] = true
+	}
+
+	for _, p := range parts2 {
+		if seen[p] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+package day18
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/search"
+)
+
+type Vec3 = grid.Vec3
+
+type Grid struct {
+	size int
+	grid *grid.Dense3[bool]
+}
+
+// Part1 returns the surface area of the droplet
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	grid := newGrid(100)
+	exposedFaces, err := parseInput(strings.TrimSpace(input), &grid)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(exposedFaces), nil
+}
+
+// Part2 returns the exterior surface area of the droplet, excluding trapped
+// air pockets
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	grid := newGrid(100)
+	if _, err := parseInput(strings.TrimSpace(input), &grid); err != nil {
+		return "", err
+	}
+	exteriorFaces, err := findExteriorSurface(&grid)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(exteriorFaces), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func newGrid(size int) Grid {
+	return Grid{
+		size: size,
+		grid: grid.NewDense3[bool](size, size, size),
+	}
+}
+
+func parseInput(input string, g *Grid) (int, error) {
+	exposedFaces := 0
+	lines := parse.Lines(input)
+	for _, line := range lines {
+		coords, err := line.SplitN(",", 3, "a cube like \"2,2,2\"")
+		if err != nil {
+			return 0, err
+		}
+		xyz, err := parse.Ints(coords)
+		if err != nil {
+			return 0, err
+		}
+
+		// insert cube into grid
+		cube := Vec3{X: xyz[0], Y: xyz[1], Z: xyz[2]}
+		if !g.grid.InBounds(cube) {
+			return 0, line.Errorf("cube is outside of the %dx%dx%d grid", g.size, g.size, g.size)
+		}
+		if g.check(cube) {
+			return 0, line.Errorf("duplicate cube")
+		}
+		g.grid.Set(cube, true)
+		exposedFaces += 6
+
+		// check for neighbors
+		for _, neighbor := range cube.Neighbours6() {
+			if g.check(neighbor) {
+				exposedFaces -= 2
+			}
+		}
+	}
+	return exposedFaces, nil
+}
+
+func findExteriorSurface(g *Grid) (int, error) {
+	// the general idea: starting at a known outside air tile (say, 0,0,0), we
+	// can implicitly find all exterior faces by flood-filling from the air tile
+	// and adding a face any time flood-fill would move into a tile in the volume
+
+	startingTile := Vec3{X: 0, Y: 0, Z: 0}
+	if g.check(startingTile) {
+		return 0, errors.New("starting tile is in the volume; try another tile")
+	}
+
+	// leave a layer of air around the grid so the flood fill can reach every
+	// side of the droplet
+	bounds := g.grid.Bounds().Grow(1)
+
+	air := search.Unweighted[Vec3](func(v Vec3) []Vec3 {
+		airNeighbors := make([]Vec3, 0)
+		for _, v := range v.Neighbours6() {
+			if bounds.Contains(v) && !g.check(v) {
+				airNeighbors = append(airNeighbors, v)
+			}
+		}
+		return airNeighbors
+	})
+
+	// flood fill the air, then count every face where the air touches the volume
+	exterior := search.BFS[Vec3](air, []Vec3{startingTile}, nil)
+
+	exteriorFaces := 0
+	for v := range exterior.Costs {
+		for _, neighbor := range v.Neighbours6() {
+			if g.check(neighbor) {
+				exteriorFaces++
+			}
+		}
+	}
+
+	return exteriorFaces, nil
+}
+
+func (g *Grid) check(v Vec3) bool {
+	filled, _ := g.grid.Get(v)
+	return filled
+}
@@ -0,0 +1,336 @@
+package day2
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+type Move string
+type Outcome string
+
+const (
+	Rock     Move = "rock"
+	Paper    Move = "paper"
+	Scissors Move = "scissors"
+	Lizard   Move = "lizard"
+	Spock    Move = "spock"
+)
+
+const (
+	Loss Outcome = "loss"
+	Tie  Outcome = "tie"
+	Win  Outcome = "win"
+)
+
+// The order in which the second column's keys are read as outcomes
+var outcomeOrder = []Outcome{Loss, Tie, Win}
+
+// Game is a rule table for a cyclic-dominance game such as rock-paper-scissors
+type Game struct {
+	Moves []Move
+	// beats[i][j] is set if Moves[i] beats Moves[j]
+	beats   [][]bool
+	index   map[Move]int
+	Scoring Scoring
+	// The letters that stand for each move in the first and second columns of
+	// the strategy guide, in the same order as Moves, and the letters that
+	// stand for a loss, tie and win when the second column is read as an
+	// outcome
+	OpponentKeys string
+	MyKeys       string
+	OutcomeKeys  string
+}
+
+// Scoring is the score for each outcome, plus a score for the move played
+type Scoring struct {
+	Outcomes map[Outcome]int
+	Moves    map[Move]int
+}
+
+type Round struct {
+	opponentMove Move
+	myMove       Move
+	outcome      Outcome
+}
+
+// GuideLine is one line of the strategy guide, before deciding how to read the
+// second column
+type GuideLine struct {
+	opponentMove Move
+	// The second column, which means something different depending on the
+	// reading
+	second parse.Field
+}
+
+// Evaluation is the total score of a strategy guide under both readings of
+// the second column. The readings are independent: a guide can make sense as
+// moves but not as outcomes (say, with more moves than outcomes), in which case
+// only that reading has an error.
+type Evaluation struct {
+	AsMoves     int
+	AsOutcomes  int
+	MovesErr    error
+	OutcomesErr error
+}
+
+// Part1 returns the strategy score when the second column is read as a move
+func Part1(r io.Reader) (string, error) {
+	game, guide, err := readGuide(r)
+	if err != nil {
+		return "", err
+	}
+	if solver.Verbosity >= solver.DEBUG {
+		dist := game.OpponentDistribution(guide)
+		best, expected := game.BestResponse(dist)
+		solver.Debugf(solver.DEBUG, "opponent plays %s\n", formatDistribution(game, dist))
+		solver.Debugf(solver.DEBUG, "best response: always play %s, for %.2f points per round\n", best, expected)
+	}
+	eval := game.Evaluate(guide)
+	if eval.MovesErr != nil {
+		return "", eval.MovesErr
+	}
+	return fmt.Sprint(eval.AsMoves), nil
+}
+
+// Part2 returns the strategy score when the second column is read as the
+// outcome of the round
+func Part2(r io.Reader) (string, error) {
+	game, guide, err := readGuide(r)
+	if err != nil {
+		return "", err
+	}
+	eval := game.Evaluate(guide)
+	if eval.OutcomesErr != nil {
+		return "", eval.OutcomesErr
+	}
+	return fmt.Sprint(eval.AsOutcomes), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+// readGuide reads the input as a rock-paper-scissors strategy guide
+func readGuide(r io.Reader) (*Game, []GuideLine, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	game := RockPaperScissors()
+	guide, err := game.ParseGuide(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	return game, guide, nil
+}
+
+// NewCyclicGame sets up a game where the moves are arranged in a circle, and
+// each move beats the half of the other moves that come before it. There must
+// be an odd number of moves so that every move beats and loses to the same
+// number of others. Moves score 1, 2, 3... in the order given.
+func NewCyclicGame(moves []Move, opponentKeys string, myKeys string) (*Game, error) {
+	n := len(moves)
+	if n%2 == 0 {
+		return nil, fmt.Errorf("a cyclic game needs an odd number of moves, got %d", n)
+	}
+	if len(opponentKeys) != n || len(myKeys) != n {
+		return nil, fmt.Errorf("expected a key for each of the %d moves", n)
+	}
+
+	g := &Game{
+		Moves:        moves,
+		beats:        make([][]bool, n),
+		index:        make(map[Move]int),
+		OpponentKeys: opponentKeys,
+		MyKeys:       myKeys,
+		OutcomeKeys:  myKeys,
+		Scoring: Scoring{
+			Outcomes: map[Outcome]int{Loss: 0, Tie: 3, Win: 6},
+			Moves:    make(map[Move]int),
+		},
+	}
+	if n > len(outcomeOrder) {
+		g.OutcomeKeys = myKeys[:len(outcomeOrder)]
+	}
+	for i, m := range moves {
+		if _, ok := g.index[m]; ok {
+			return nil, fmt.Errorf("duplicate move %s", m)
+		}
+		g.index[m] = i
+		g.Scoring.Moves[m] = i + 1
+
+		g.beats[i] = make([]bool, n)
+		for step := 1; step <= n/2; step++ {
+			g.beats[i][(i-step+n)%n] = true
+		}
+	}
+
+	return g, nil
+}
+
+// RockPaperScissors is the game from the puzzle
+func RockPaperScissors() *Game {
+	g, _ := NewCyclicGame([]Move{Rock, Paper, Scissors}, "ABC", "XYZ")
+	return g
+}
+
+// RockPaperScissorsLizardSpock is the five move variant. Its strategy guides
+// use A-E and V-Z for the moves, and X-Z for the outcomes.
+func RockPaperScissorsLizardSpock() *Game {
+	g, _ := NewCyclicGame([]Move{Rock, Spock, Paper, Lizard, Scissors}, "ABCDE", "VWXYZ")
+	g.OutcomeKeys = "XYZ"
+	return g
+}
+
+// Outcome returns the outcome of a round for the player playing myMove
+func (g *Game) Outcome(opponentMove Move, myMove Move) Outcome {
+	o, m := g.index[opponentMove], g.index[myMove]
+	switch {
+	case g.beats[m][o]:
+		{
+			return Win
+		}
+	case g.beats[o][m]:
+		{
+			return Loss
+		}
+	default:
+		{
+			return Tie
+		}
+	}
+}
+
+// MoveFor returns the move that gives outcome against opponentMove. If more
+// than one move does, the highest scoring one is returned.
+func (g *Game) MoveFor(opponentMove Move, outcome Outcome) Move {
+	var best Move
+	found := false
+	for _, m := range g.Moves {
+		if g.Outcome(opponentMove, m) != outcome {
+			continue
+		}
+		if !found || g.Scoring.Moves[m] > g.Scoring.Moves[best] {
+			best = m
+			found = true
+		}
+	}
+	return best
+}
+
+func (g *Game) Score(round Round) int {
+	return g.Scoring.Outcomes[round.outcome] + g.Scoring.Moves[round.myMove]
+}
+
+// ParseGuide reads the strategy guide. Only the first column is interpreted
+// here; see Evaluate for the second.
+func (g *Game) ParseGuide(input string) ([]GuideLine, error) {
+	lines := parse.Lines(input)
+	guide := make([]GuideLine, 0, len(lines))
+	for _, line := range lines {
+		if len(line.Text) == 0 {
+			continue
+		}
+		if len(line.Text) != 3 || line.Text[1] != ' ' {
+			return nil, line.Errorf("expected two columns like \"A X\", got %q", line.Text)
+		}
+
+		opponentKey := strings.IndexByte(g.OpponentKeys, line.Text[0])
+		if opponentKey < 0 {
+			return nil, line.Errorf("unknown opponent move %q", line.Text[0])
+		}
+		second := line.Slice(2, 3)
+		if !strings.Contains(g.MyKeys, second.Text) && !strings.Contains(g.OutcomeKeys, second.Text) {
+			return nil, second.Errorf("unknown move or outcome %q", second.Text)
+		}
+
+		guide = append(guide, GuideLine{opponentMove: g.Moves[opponentKey], second: second})
+	}
+	return guide, nil
+}
+
+// Evaluate scores the guide reading the second column both as the move to play
+// and as the outcome to aim for, in a single pass. A reading stops at the first
+// line it can't make sense of, without affecting the other.
+func (g *Game) Evaluate(guide []GuideLine) Evaluation {
+	var eval Evaluation
+	for _, line := range guide {
+		if eval.MovesErr == nil {
+			if myKey := strings.Index(g.MyKeys, line.second.Text); myKey < 0 {
+				eval.MovesErr = line.second.Errorf("unknown move %q", line.second.Text)
+			} else {
+				asMove := g.play(line.opponentMove, g.Moves[myKey])
+				eval.AsMoves += g.Score(asMove)
+				solver.Debugf(solver.TRACE, "as a move: %v\n", asMove)
+			}
+		}
+
+		if eval.OutcomesErr == nil {
+			if outcomeKey := strings.Index(g.OutcomeKeys, line.second.Text); outcomeKey < 0 {
+				eval.OutcomesErr = line.second.Errorf("unknown outcome %q", line.second.Text)
+			} else {
+				outcome := outcomeOrder[outcomeKey]
+				asOutcome := g.play(line.opponentMove, g.MoveFor(line.opponentMove, outcome))
+				eval.AsOutcomes += g.Score(asOutcome)
+				solver.Debugf(solver.TRACE, "as an outcome: %v\n", asOutcome)
+			}
+		}
+	}
+	return eval
+}
+
+func (g *Game) play(opponentMove Move, myMove Move) Round {
+	return Round{
+		opponentMove: opponentMove,
+		myMove:       myMove,
+		outcome:      g.Outcome(opponentMove, myMove),
+	}
+}
+
+// OpponentDistribution returns how often the opponent plays each move in the
+// guide, as a fraction of the rounds
+func (g *Game) OpponentDistribution(guide []GuideLine) map[Move]float64 {
+	dist := make(map[Move]float64)
+	for _, line := range guide {
+		dist[line.opponentMove] += 1 / float64(len(guide))
+	}
+	return dist
+}
+
+// BestResponse returns the move that scores the most on average against an
+// opponent who picks their moves with the given probabilities, along with the
+// average score. Against a fixed distribution, always playing the same move is
+// as good as any mixed strategy.
+func (g *Game) BestResponse(dist map[Move]float64) (Move, float64) {
+	best, bestScore := g.Moves[0], 0.0
+	for i, m := range g.Moves {
+		expected := 0.0
+		for opponentMove, p := range dist {
+			expected += p * float64(g.Score(g.play(opponentMove, m)))
+		}
+		if i == 0 || expected > bestScore {
+			best, bestScore = m, expected
+		}
+	}
+	return best, bestScore
+}
+
+func formatDistribution(g *Game, dist map[Move]float64) string {
+	moves := make([]Move, 0, len(dist))
+	for m := range dist {
+		moves = append(moves, m)
+	}
+	sort.Slice(moves, func(i, j int) bool { return g.index[moves[i]] < g.index[moves[j]] })
+
+	parts := make([]string, len(moves))
+	for i, m := range moves {
+		parts[i] = fmt.Sprintf("%s %.0f%%", m, dist[m]*100)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,107 @@
+package day2
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+func TestFiveMoveOutcomes(t *testing.T) {
+	g := RockPaperScissorsLizardSpock()
+	beats := map[Move][]Move{
+		Rock:     {Scissors, Lizard},
+		Paper:    {Rock, Spock},
+		Scissors: {Paper, Lizard},
+		Lizard:   {Spock, Paper},
+		Spock:    {Scissors, Rock},
+	}
+	for m, losers := range beats {
+		for _, loser := range losers {
+			if got := g.Outcome(loser, m); got != Win {
+				t.Errorf("%s against %s: got %s, want a win", m, loser, got)
+			}
+			if got := g.Outcome(m, loser); got != Loss {
+				t.Errorf("%s against %s: got %s, want a loss", loser, m, got)
+			}
+		}
+		if got := g.Outcome(m, m); got != Tie {
+			t.Errorf("%s against itself: got %s, want a tie", m, got)
+		}
+	}
+}
+
+func TestFiveMoveGuide(t *testing.T) {
+	g := RockPaperScissorsLizardSpock()
+
+	// V and W are only moves, so the guide can't be read as outcomes
+	guide, err := g.ParseGuide("A V\nB Z\nE X\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eval := g.Evaluate(guide)
+	if eval.MovesErr != nil || eval.AsMoves != 4+5+3 {
+		t.Errorf("got %d (%v) reading moves, want 12", eval.AsMoves, eval.MovesErr)
+	}
+	var parseErr *parse.Error
+	if !errors.As(eval.OutcomesErr, &parseErr) || parseErr.Line != 1 || parseErr.Col != 3 {
+		t.Errorf("expected the outcome reading to fail at line 1, col 3, got %v", eval.OutcomesErr)
+	}
+
+	guide, err = g.ParseGuide("A X\nC Z\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eval = g.Evaluate(guide)
+	if eval.MovesErr != nil || eval.AsMoves != 9+11 {
+		t.Errorf("got %d (%v) reading moves, want 20", eval.AsMoves, eval.MovesErr)
+	}
+	// Both lizard and scissors lose to rock and beat paper, and scissors
+	// scores more
+	if eval.OutcomesErr != nil || eval.AsOutcomes != 5+11 {
+		t.Errorf("got %d (%v) reading outcomes, want 16", eval.AsOutcomes, eval.OutcomesErr)
+	}
+
+	if _, err := g.ParseGuide("A U\n"); err == nil {
+		t.Error("expected an error for a key that is neither a move nor an outcome")
+	}
+}
+
+func TestBestResponse(t *testing.T) {
+	rps := RockPaperScissors()
+	rpsls := RockPaperScissorsLizardSpock()
+	tests := []struct {
+		game *Game
+		dist map[Move]float64
+		move Move
+		want float64
+	}{
+		{rps, map[Move]float64{Rock: 1}, Paper, 8},
+		{rps, map[Move]float64{Rock: 0.5, Scissors: 0.5}, Rock, 5.5},
+		// Against a uniform opponent every move wins and ties equally often, so
+		// the highest scoring move is best
+		{rpsls, map[Move]float64{Rock: 0.2, Spock: 0.2, Paper: 0.2, Lizard: 0.2, Scissors: 0.2}, Scissors, 8},
+	}
+	for _, test := range tests {
+		move, expected := test.game.BestResponse(test.dist)
+		if move != test.move || math.Abs(expected-test.want) > 1e-9 {
+			t.Errorf("against %v: got %s for %.2f, want %s for %.2f", test.dist, move, expected, test.move, test.want)
+		}
+	}
+
+	guide, err := rps.ParseGuide("A Y\nB X\nC Z\nA X\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := rps.OpponentDistribution(guide)
+	if math.Abs(dist[Rock]-0.5) > 1e-9 || math.Abs(dist[Paper]-0.25) > 1e-9 {
+		t.Errorf("got distribution %v", dist)
+	}
+}
+
+func TestCyclicGameNeedsOddMoves(t *testing.T) {
+	if _, err := NewCyclicGame([]Move{Rock, Paper}, "AB", "XY"); err == nil {
+		t.Error("expected an error for an even number of moves")
+	}
+}
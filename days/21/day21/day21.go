@@ -0,0 +1,312 @@
+package day21
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+type Node interface {
+	GetType() string
+	GetName() string
+	Eval() int
+	Contains(string) bool
+}
+
+type Value struct {
+	name  string
+	value int
+}
+
+type Variable struct {
+	name  string
+	value string
+}
+
+type Operation struct {
+	name        string
+	operator    string
+	left, right Node
+}
+
+// Part1 returns the number yelled by the root monkey
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	nodes, err := parseInput(strings.TrimSpace(input), false)
+	if err != nil {
+		return "", err
+	}
+	root := replaceRoot(nodes, "root")
+	return fmt.Sprint(root.Eval()), nil
+}
+
+// Part2 returns the number humn must yell for root's equality test to pass
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	nodes, err := parseInput(strings.TrimSpace(input), true)
+	if err != nil {
+		return "", err
+	}
+	root := replaceRoot(nodes, "root")
+	reorder(&root, "humn")
+	return fmt.Sprint(root.right.Eval()), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+// Parse the monkeys' jobs. If solveForHumn is set, humn is left as a variable
+// and root becomes an equality
+func parseInput(input string, solveForHumn bool) (map[string]Node, error) {
+	lines := parse.Lines(input)
+	nodes := make(map[string]Node)
+	references := make([]parse.Field, 0)
+
+	for _, line := range lines {
+		parts, err := line.SplitN(": ", 2, "a job like \"root: pppw + sjmn\"")
+		if err != nil {
+			return nil, err
+		}
+		name := parts[0].Text
+		if _, ok := nodes[name]; ok {
+			return nil, parts[0].Errorf("duplicate monkey %q", name)
+		}
+		node, refs, err := parseNode(name, parts[1], solveForHumn)
+		if err != nil {
+			return nil, err
+		}
+		nodes[name] = node
+		references = append(references, refs...)
+	}
+
+	for _, ref := range references {
+		if _, ok := nodes[ref.Text]; !ok {
+			return nil, ref.Errorf("unknown monkey %q", ref.Text)
+		}
+	}
+	if root, ok := nodes["root"]; !ok || root.GetType() != "operation" {
+		return nil, errors.New("root must be waiting on two other monkeys")
+	}
+	if _, ok := nodes["humn"]; solveForHumn && !ok {
+		return nil, errors.New("no monkey named humn")
+	}
+
+	return nodes, nil
+}
+
+// Parse a single job, also returning the monkeys it waits on
+func parseNode(name string, input parse.Field, solveForHumn bool) (Node, []parse.Field, error) {
+	components := input.Split(" ")
+	if len(components) == 1 {
+		if solveForHumn && name == "humn" {
+			return Variable{name: name, value: name}, nil, nil
+		}
+		value, err := components[0].Int()
+		if err != nil {
+			return nil, nil, err
+		}
+		return Value{name, value}, nil, nil
+	} else if len(components) == 3 {
+		refs := make([]parse.Field, 0, 2)
+		var left, right Node
+		leftValue, err := strconv.Atoi(components[0].Text)
+		if err != nil {
+			left = Variable{components[0].Text, components[0].Text}
+			refs = append(refs, components[0])
+		} else {
+			left = Value{"", leftValue}
+		}
+		rightValue, err := strconv.Atoi(components[2].Text)
+		if err != nil {
+			right = Variable{components[2].Text, components[2].Text}
+			refs = append(refs, components[2])
+		} else {
+			right = Value{"", rightValue}
+		}
+
+		operator := components[1].Text
+		if operator != "+" && operator != "-" && operator != "*" && operator != "/" {
+			return nil, nil, components[1].Errorf("unrecognized operator %q", operator)
+		}
+		if solveForHumn && name == "root" {
+			operator = "="
+		}
+
+		return Operation{name: name, operator: operator, left: left, right: right}, refs, nil
+	} else {
+		return nil, nil, input.Errorf("expected a number or an operation like \"pppw + sjmn\", got %q", input.Text)
+	}
+}
+
+func replaceRoot(nodes map[string]Node, root string) Operation {
+	var replace func(node Node) Node
+	replace = func(node Node) Node {
+		switch node.GetType() {
+		case "value":
+			{
+				return node
+			}
+		case "variable":
+			{
+				value := nodes[node.(Variable).value]
+				if value.GetType() != "variable" {
+					return replace(value)
+				}
+				return node
+			}
+		case "operation":
+			{
+				op := node.(Operation)
+				return Operation{
+					name:     op.name,
+					operator: op.operator,
+					left:     replace(op.left),
+					right:    replace(op.right),
+				}
+			}
+		default:
+			{
+				panic(errors.New("unrecognized node"))
+			}
+		}
+	}
+
+	return replace(nodes[root]).(Operation)
+}
+
+func reorder(root *Operation, target string) {
+	if root.operator != "=" {
+		panic(errors.New("root must be an equality"))
+	}
+
+	leftContains := root.left.Contains(target)
+	rightContains := root.right.Contains(target)
+	if !leftContains && !rightContains {
+		panic(errors.New("target not found"))
+	}
+
+	if rightContains {
+		root.left, root.right = root.right, root.left
+	}
+
+	inverseOperators := map[string]string{
+		"+": "-",
+		"-": "+",
+		"*": "/",
+		"/": "*",
+	}
+
+	// Rotate the tree until the left side contains just the target node
+	for root.left.GetName() != target {
+		leftSubchildContainsTarget := root.left.(Operation).left.Contains(target)
+		var toMove Node
+		if leftSubchildContainsTarget {
+			toMove = root.left.(Operation).right
+		} else {
+			toMove = root.left.(Operation).left
+		}
+
+		op := root.left.(Operation).operator
+		if op == "+" || op == "*" || leftSubchildContainsTarget {
+			root.right = Operation{"", inverseOperators[op], root.right, toMove}
+		} else {
+			root.right = Operation{"", op, toMove, root.right}
+		}
+
+		if leftSubchildContainsTarget {
+			root.left = root.left.(Operation).left
+		} else {
+			root.left = root.left.(Operation).right
+		}
+	}
+}
+
+func (v Value) Eval() int {
+	return v.value
+}
+
+func (v Value) GetType() string {
+	return "value"
+}
+
+func (v Value) GetName() string {
+	return v.name
+}
+
+func (v Value) Contains(name string) bool {
+	return v.GetName() == name
+}
+
+func (v Variable) Eval() int {
+	panic(errors.New("tried to eval a variable; it must be replaced first"))
+}
+
+func (v Variable) GetType() string {
+	return "variable"
+}
+
+func (v Variable) GetName() string {
+	return v.name
+}
+
+func (v Variable) Contains(name string) bool {
+	return v.GetName() == name
+}
+
+func (o Operation) Eval() int {
+	switch o.operator {
+	case "+":
+		{
+			return o.left.Eval() + o.right.Eval()
+		}
+	case "-":
+		{
+			return o.left.Eval() - o.right.Eval()
+		}
+	case "*":
+		{
+			return o.left.Eval() * o.right.Eval()
+		}
+	case "/":
+		{
+			return o.left.Eval() / o.right.Eval()
+		}
+	case "=":
+		{
+			equal := o.left.Eval() == o.right.Eval()
+			if equal {
+				return 1
+			} else {
+				return 0
+			}
+		}
+	default:
+		{
+			panic(errors.New("unrecognized operator"))
+		}
+	}
+}
+
+func (o Operation) GetType() string {
+	return "operation"
+}
+
+func (o Operation) GetName() string {
+	return o.name
+}
+
+func (o Operation) Contains(name string) bool {
+	return o.GetName() == name || o.left.Contains(name) || o.right.Contains(name)
+}
@@ -0,0 +1,214 @@
+package day23
+
+import (
+	"io"
+	//	"errors"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/FaideWW/aoc-2022/grid"
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+type Direction int
+
+const (
+	NORTH Direction = 0
+	SOUTH Direction = 1
+	WEST  Direction = 2
+	EAST  Direction = 3
+)
+
+type Position = grid.Vec2
+
+type Grove struct {
+	elves  grid.Sparse[bool]
+	bounds grid.Box
+}
+
+// Part1 returns the number of empty tiles in the bounding box of the elves
+// after 10 rounds
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	grove, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	grove.run(10)
+	return fmt.Sprint(grove.computeEmptyTiles()), nil
+}
+
+// Part2 returns the first round in which no elf moves
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	grove, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	turnsCompleted := grove.run(math.MaxInt)
+	return fmt.Sprint(turnsCompleted + 1), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) (Grove, error) {
+	for _, line := range parse.Lines(input) {
+		for i := 0; i < len(line.Text); i++ {
+			if c := line.Text[i]; c != '#' && c != '.' {
+				return Grove{}, parse.Errorf(line.Line, line.Col+i, "unexpected character %q", c)
+			}
+		}
+	}
+	elves := grid.ParseSparse(input, func(p grid.Vec2, c byte) (bool, bool) {
+		return true, c == '#'
+	})
+	if len(elves) == 0 {
+		return Grove{}, errors.New("there are no elves in the grove")
+	}
+
+	g := Grove{
+		elves: elves,
+	}
+	g.recomputeBoundingBox()
+
+	return g, nil
+}
+
+type Move struct {
+	to   Position
+	from Position
+}
+
+func (g *Grove) run(turns int) int {
+	i := 0
+	for ; i < turns; i++ {
+		seenDestinations := make(map[Position][]Position)
+		for elfPos := range g.elves {
+			if g.isAlone(elfPos) {
+				continue
+			}
+			for j := 0; j < 4; j++ {
+				direction := (i + j) % 4
+				candidates := getCandidates(elfPos, Direction(direction))
+				occupied := false
+				for _, candidate := range candidates {
+					if g.elves[candidate] {
+						occupied = true
+					}
+				}
+
+				if !occupied {
+					destination := candidates[1]
+					_, ok := seenDestinations[destination]
+					if !ok {
+						seenDestinations[destination] = make([]Position, 1)
+						seenDestinations[destination][0] = elfPos
+					} else {
+						seenDestinations[destination] = append(seenDestinations[destination], elfPos)
+					}
+					break
+				}
+			}
+		}
+
+		// If no one tried to move, we're done
+		if len(seenDestinations) == 0 {
+			break
+		}
+
+		for dest, origin := range seenDestinations {
+			if len(origin) > 1 {
+				// If more than one elf tried to move to this position, no one moves
+				continue
+			}
+
+			pos := origin[0]
+			g.elves[dest] = true
+			delete(g.elves, pos)
+		}
+		g.recomputeBoundingBox()
+	}
+	return i
+}
+
+func (g *Grove) recomputeBoundingBox() {
+	g.bounds = g.elves.Bounds()
+}
+
+func (g *Grove) computeEmptyTiles() int {
+	return g.bounds.Area() - len(g.elves)
+}
+
+func (g *Grove) isAlone(p Position) bool {
+	for _, neighbor := range p.Neighbours8() {
+		if g.elves[neighbor] {
+			return false
+		}
+	}
+	return true
+}
+
+func getCandidates(p Position, direction Direction) []Position {
+	switch direction {
+	case NORTH:
+		{
+			return []Position{
+				{X: p.X - 1, Y: p.Y - 1},
+				{X: p.X, Y: p.Y - 1},
+				{X: p.X + 1, Y: p.Y - 1},
+			}
+		}
+	case SOUTH:
+		{
+			return []Position{
+				{X: p.X - 1, Y: p.Y + 1},
+				{X: p.X, Y: p.Y + 1},
+				{X: p.X + 1, Y: p.Y + 1},
+			}
+		}
+	case WEST:
+		{
+			return []Position{
+				{X: p.X - 1, Y: p.Y - 1},
+				{X: p.X - 1, Y: p.Y},
+				{X: p.X - 1, Y: p.Y + 1},
+			}
+		}
+	case EAST:
+		{
+			return []Position{
+				{X: p.X + 1, Y: p.Y - 1},
+				{X: p.X + 1, Y: p.Y},
+				{X: p.X + 1, Y: p.Y + 1},
+			}
+		}
+	}
+	panic(errors.New("unrecognized direction"))
+}
+
+func (g *Grove) print() string {
+	var output string
+	for y := g.bounds.Min.Y; y < g.bounds.Max.Y+1; y++ {
+		line := ""
+		for x := g.bounds.Min.X; x < g.bounds.Max.X+1; x++ {
+			if g.elves[Position{X: x, Y: y}] {
+				line += "#"
+			} else {
+				line += "."
+			}
+		}
+		output += line + "\n"
+	}
+
+	return output
+}
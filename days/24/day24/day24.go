@@ -0,0 +1,368 @@
+package day24
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/search"
+)
+
+type Direction int
+
+const (
+	NORTH Direction = 0
+	EAST  Direction = 1
+	SOUTH Direction = 2
+	WEST  Direction = 3
+)
+
+type Position = grid.Vec2
+
+type Blizzards map[Position]byte
+
+type Valley struct {
+	width         int
+	height        int
+	interior      grid.Box
+	entrance      Position
+	exit          Position
+	blizzardCache map[int]Blizzards
+}
+
+type State struct {
+	pos  Position
+	time int
+}
+
+// Part1 returns the fewest minutes needed to reach the goal
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	valley, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(valley.findPath(valley.entrance, valley.exit, 0)), nil
+}
+
+// Part2 returns the fewest minutes needed to reach the goal, return to the
+// start and reach the goal again
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	valley, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	steps1 := valley.findPath(valley.entrance, valley.exit, 0)
+	steps2 := valley.findPath(valley.exit, valley.entrance, steps1)
+	steps3 := valley.findPath(valley.entrance, valley.exit, steps2)
+	return fmt.Sprint(steps3), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) (Valley, error) {
+	lines := parse.Lines(input)
+	if len(lines) < 3 {
+		return Valley{}, fmt.Errorf("expected at least 3 rows, got %d", len(lines))
+	}
+	blizzards := make(Blizzards)
+
+	var entrance Position
+	var exit Position
+	width := len(lines[0].Text)
+	for y := 0; y < len(lines); y++ {
+		line := lines[y].Text
+		if len(line) != width {
+			return Valley{}, lines[y].Errorf("expected %d columns, got %d", width, len(line))
+		}
+		for x := 0; x < len(line); x++ {
+			tile := line[x]
+			pos := Position{X: x, Y: y}
+			onWall := x == 0 || x == width-1 || y == 0 || y == len(lines)-1
+			if onWall && tile != '#' && tile != '.' {
+				return Valley{}, parse.Errorf(lines[y].Line, lines[y].Col+x, "expected a wall, got %q", tile)
+			}
+			if onWall && tile == '.' && (x == 0 || x == width-1) {
+				return Valley{}, parse.Errorf(lines[y].Line, lines[y].Col+x, "the valley's sides must be walls")
+			}
+			switch tile {
+			case '>':
+				{
+					blizzards[pos] = blizzards[pos] | (1 << EAST)
+				}
+			case '<':
+				{
+					blizzards[pos] = blizzards[pos] | (1 << WEST)
+				}
+			case '^':
+				{
+					blizzards[pos] = blizzards[pos] | (1 << NORTH)
+				}
+			case 'v':
+				{
+					blizzards[pos] = blizzards[pos] | (1 << SOUTH)
+				}
+			case '.':
+				{
+					if y == 0 {
+						if entrance != (Position{}) {
+							return Valley{}, parse.Errorf(lines[y].Line, lines[y].Col+x, "the valley has more than one entrance")
+						}
+						entrance = pos
+					} else if y == len(lines)-1 {
+						if exit != (Position{}) {
+							return Valley{}, parse.Errorf(lines[y].Line, lines[y].Col+x, "the valley has more than one exit")
+						}
+						exit = pos
+					}
+				}
+			case '#':
+				{
+					if !onWall {
+						return Valley{}, parse.Errorf(lines[y].Line, lines[y].Col+x, "unexpected wall inside the valley")
+					}
+				}
+			default:
+				{
+					return Valley{}, parse.Errorf(lines[y].Line, lines[y].Col+x, "unexpected character %q", tile)
+				}
+			}
+		}
+	}
+	if entrance == (Position{}) {
+		return Valley{}, lines[0].Errorf("the valley has no entrance")
+	}
+	if exit == (Position{}) {
+		return Valley{}, lines[len(lines)-1].Errorf("the valley has no exit")
+	}
+
+	blizzardCache := make(map[int]Blizzards)
+	blizzardCache[0] = blizzards
+
+	return Valley{
+		height: len(lines),
+		width:  width,
+		interior: grid.Box{
+			Min: Position{X: 1, Y: 1},
+			Max: Position{X: width - 2, Y: len(lines) - 2},
+		},
+		entrance:      entrance,
+		exit:          exit,
+		blizzardCache: blizzardCache,
+	}, nil
+}
+
+// A* search for shortest path from start to goal, starting at initialTime
+// (important for blizzard positioning)
+func (v *Valley) findPath(start Position, goal Position, initialTime int) int {
+	if _, ok := v.blizzardCache[initialTime]; !ok {
+		v.blizzardCache[initialTime] = advanceBlizzards(v.blizzardCache[0], v.width, v.height, initialTime)
+	}
+
+	heuristic := func(nextState State) int {
+		// heruistic is the manhattan distance to the goal
+		return goal.Sub(nextState.pos).Manhattan()
+	}
+
+	getNextPositions := func(state State) []Position {
+		nextTime := (state.time + 1)
+		// I believe there's a repeating cycle of blizzards based on the width of
+		// the valley, since blizzards move at a constant rate in one direction
+		// forever. However, this optimization doesn't seem necessary (it runs fast
+		// enough without) and using the below line doesn't give the correct
+		// result, so noting that there is time to be gained by figuring this out.
+		// cycleTime := (state.time + 1) % (v.width - 2)
+		b, ok := v.blizzardCache[nextTime]
+		if !ok {
+			v.blizzardCache[nextTime] = advanceBlizzards(v.blizzardCache[0], v.width, v.height, nextTime)
+			b = v.blizzardCache[nextTime]
+		}
+
+		candidates := make([]Position, 0)
+
+		// neighbours are ordered north, east, south, west
+		for _, next := range state.pos.Neighbours4() {
+			if v.inBounds(next) && b[next] == 0 {
+				candidates = append(candidates, next)
+			}
+		}
+
+		if b[state.pos] == 0 {
+			candidates = append(candidates, state.pos)
+		}
+
+		return candidates
+	}
+
+	moves := search.Unweighted[State](func(state State) []State {
+		next := make([]State, 0)
+		for _, candidate := range getNextPositions(state) {
+			next = append(next, State{candidate, state.time + 1})
+		}
+		return next
+	})
+
+	startingState := State{start, initialTime}
+	result := search.AStar[State](moves, []State{startingState}, func(state State) bool {
+		return state.pos == goal
+	}, heuristic)
+
+	return result.Goal.time
+}
+
+// Advance all of the blizzards 1 step
+func advanceBlizzards(blizzards Blizzards, width int, height int, steps int) Blizzards {
+	nextBlizzards := make(Blizzards)
+	// There's probably an optimized bitwise operation that can instantly update
+	// the entire grid, but for now we'll do it one at a time.
+
+	// Assumption: the entrance and exit are considered "walls" for the purposes
+	// of wrapping blizzards around the grid. Ie, a northward blizzard can't
+	// wander into the entrance tile (it will wrap to the bottom after (1,1)
+	for pos, mask := range blizzards {
+		if ((mask >> NORTH) & 1) == 1 {
+			north := Position{
+				X: pos.X,
+				Y: wrapInt(pos.Y-steps, 1, height-1),
+			}
+			nextBlizzards[north] = nextBlizzards[north] | (1 << NORTH)
+		}
+		if ((mask >> EAST) & 1) == 1 {
+			east := Position{
+				X: wrapInt(pos.X+steps, 1, width-1),
+				Y: pos.Y,
+			}
+			nextBlizzards[east] = nextBlizzards[east] | (1 << EAST)
+		}
+		if ((mask >> SOUTH) & 1) == 1 {
+			south := Position{
+				X: pos.X,
+				Y: wrapInt(pos.Y+steps, 1, height-1),
+			}
+			nextBlizzards[south] = nextBlizzards[south] | (1 << SOUTH)
+		}
+		if ((mask >> WEST) & 1) == 1 {
+			west := Position{
+				X: wrapInt(pos.X-steps, 1, width-1),
+				Y: pos.Y,
+			}
+			nextBlizzards[west] = nextBlizzards[west] | (1 << WEST)
+		}
+	}
+
+	return nextBlizzards
+}
+
+// Wraps a value around [min, max)
+func wrapInt(x, min, max int) int {
+	interval := max - min
+	offset := x - min
+	return min + (interval+offset%interval)%interval
+}
+
+func (v *Valley) inBounds(p Position) bool {
+	if p == v.entrance {
+		return true
+	}
+	if p == v.exit {
+		return true
+	}
+	return v.interior.Contains(p)
+}
+
+func (v *Valley) print(blizzards Blizzards, player Position) string {
+	var output string
+	for y := 0; y < v.height; y++ {
+		var line string
+		for x := 0; x < v.width; x++ {
+			pos := Position{X: x, Y: y}
+			if player == pos {
+				line += "E"
+				continue
+			}
+			switch y {
+			case 0:
+				{
+					if pos == v.entrance {
+						line += "."
+					} else {
+						line += "#"
+					}
+				}
+			case v.height - 1:
+				{
+					if pos == v.exit {
+						line += "."
+					} else {
+						line += "#"
+					}
+
+				}
+			default:
+				{
+					switch x {
+					case 0:
+						{
+							line += "#"
+						}
+					case v.width - 1:
+						{
+							line += "#"
+						}
+					default:
+						{
+							activeBlizzards := 0
+							var tile string
+							// count how many blizzards are active
+							if ((blizzards[pos] >> NORTH) & 1) == 1 {
+								activeBlizzards++
+								tile = "^"
+							}
+							if ((blizzards[pos] >> EAST) & 1) == 1 {
+								activeBlizzards++
+								tile = ">"
+							}
+							if ((blizzards[pos] >> SOUTH) & 1) == 1 {
+								activeBlizzards++
+								tile = "v"
+							}
+							if ((blizzards[pos] >> WEST) & 1) == 1 {
+								activeBlizzards++
+								tile = "<"
+							}
+							switch activeBlizzards {
+							case 0:
+								{
+									line += "."
+								}
+							case 1:
+								{
+									line += tile
+								}
+							default:
+								{
+									line += fmt.Sprint(activeBlizzards)
+								}
+							}
+						}
+					}
+
+				}
+			}
+		}
+		output += fmt.Sprintf("%s\n", line)
+	}
+	return output
+}
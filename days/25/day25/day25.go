@@ -0,0 +1,118 @@
+package day25
+
+import (
+	"io"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+var snafuTable = map[byte]int{
+	'=': -2,
+	'-': -1,
+	'0': 0,
+	'1': 1,
+	'2': 2,
+}
+
+var intTable = map[int]byte{
+	-2: '=',
+	-1: '-',
+	0:  '0',
+	1:  '1',
+	2:  '2',
+}
+
+// Part1 returns the sum of the fuel requirements as a SNAFU number. Day 25
+// has no second part
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	snafus, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+
+	sum := 0
+	for _, s := range snafus {
+		sum += snafuToInt(s)
+	}
+
+	return intToSnafu(sum), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) ([]string, error) {
+	lines := parse.Lines(input)
+	snafus := make([]string, len(lines))
+	for i, line := range lines {
+		if line.Text == "" {
+			return nil, line.Errorf("expected a SNAFU number")
+		}
+		for j := 0; j < len(line.Text); j++ {
+			if _, ok := snafuTable[line.Text[j]]; !ok {
+				return nil, parse.Errorf(line.Line, line.Col+j, "invalid SNAFU digit %q", line.Text[j])
+			}
+		}
+		snafus[i] = line.Text
+	}
+	return snafus, nil
+}
+
+func snafuToInt(s string) int {
+	value := 0
+	for i := 0; i < len(s); i++ {
+		digitValue := snafuTable[s[len(s)-1-i]]
+		value += digitValue * intPow(5, i)
+	}
+	return value
+}
+
+func intToSnafu(i int) string {
+	remainingValue := i
+	snafu := ""
+	digits := make([]int, 0)
+	// currentDigit := 0
+	for remainingValue != 0 {
+		carry := 0
+		digitAtPlace := remainingValue % 5
+
+		if digitAtPlace > 2 {
+			carry = 5
+			digitAtPlace -= carry
+		}
+		digits = append(digits, digitAtPlace)
+		nextValue := ((remainingValue + carry) / 5)
+		remainingValue = nextValue
+	}
+
+	for i := 0; i < len(digits); i++ {
+		snafu += string(intTable[digits[len(digits)-1-i]])
+
+	}
+
+	return snafu
+}
+
+func intPow(base, exp int) int {
+	if exp == 0 {
+		return 1
+	}
+	result := base
+	for i := 2; i <= exp; i++ {
+		result *= base
+	}
+	return result
+}
+
+func reverse[S ~[]E, E any](s S) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
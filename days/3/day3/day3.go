@@ -0,0 +1,304 @@
+package day3
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+// The number of elves in each group in part 2
+const GROUP_SIZE = 3
+
+// ItemSet is a set of item types, with the bit for each item's priority set
+// (bits 1-26 for a-z and 27-52 for A-Z)
+type ItemSet uint64
+
+type Rucksack struct {
+	compartments [2]ItemSet
+	// The line of the input the rucksack was read from
+	line int
+}
+
+type RucksackGroup struct {
+	sacks     []Rucksack
+	badgeItem byte
+}
+
+// Part1 returns the total priority of the items duplicated within each
+// rucksack
+func Part1(r io.Reader) (string, error) {
+	rucksacks, err := ReadRucksacks(r)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(getTotalPriority(rucksacks)), nil
+}
+
+// Part2 returns the total priority of each group's badge item
+func Part2(r io.Reader) (string, error) {
+	rucksacks, err := ReadRucksacks(r)
+	if err != nil {
+		return "", err
+	}
+	groups, err := GroupRucksacks(rucksacks, GROUP_SIZE)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(BadgePriority(groups)), nil
+}
+
+// ReadRucksacks reads the contents of each rucksack, one per line
+func ReadRucksacks(r io.Reader) ([]Rucksack, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(input)
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) ([]Rucksack, error) {
+	lines := parse.Lines(input)
+	sacks := make([]Rucksack, 0, len(lines))
+	for _, line := range lines {
+		if len(line.Text) == 0 {
+			continue
+		}
+		trimmed := line.TrimSpace()
+		if len(trimmed.Text) == 0 {
+			return nil, line.Errorf("rucksack has no items")
+		}
+		line = trimmed
+		for i := 0; i < len(line.Text); i++ {
+			if getItemPriority(line.Text[i]) == 0 {
+				return nil, parse.Errorf(line.Line, line.Col+i, "invalid item %q", line.Text[i])
+			}
+		}
+		if len(line.Text)%2 != 0 {
+			return nil, line.Errorf("rucksack has an odd number of items (%d)", len(line.Text))
+		}
+
+		half := len(line.Text) / 2
+		sacks = append(sacks, Rucksack{
+			compartments: [2]ItemSet{ItemSetOf(line.Text[:half]), ItemSetOf(line.Text[half:])},
+			line:         line.Line,
+		})
+	}
+
+	return sacks, nil
+}
+
+// ItemSetOf returns the set of items in s. Characters that aren't items are
+// ignored.
+func ItemSetOf(s string) ItemSet {
+	var set ItemSet
+	for i := 0; i < len(s); i++ {
+		if p := getItemPriority(s[i]); p != 0 {
+			set |= 1 << p
+		}
+	}
+	return set
+}
+
+func (s ItemSet) Union(other ItemSet) ItemSet {
+	return s | other
+}
+
+func (s ItemSet) Intersect(other ItemSet) ItemSet {
+	return s & other
+}
+
+// Difference returns the items in s that aren't in other
+func (s ItemSet) Difference(other ItemSet) ItemSet {
+	return s &^ other
+}
+
+func (s ItemSet) Has(item byte) bool {
+	p := getItemPriority(item)
+	return p != 0 && s&(1<<p) != 0
+}
+
+func (s ItemSet) Len() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// Items returns the items in the set, in priority order
+func (s ItemSet) Items() []byte {
+	items := make([]byte, 0, s.Len())
+	for rest := uint64(s); rest != 0; rest &= rest - 1 {
+		items = append(items, itemWithPriority(bits.TrailingZeros64(rest)))
+	}
+	return items
+}
+
+// Priority returns the total priority of the items in the set
+func (s ItemSet) Priority() int {
+	sum := 0
+	for rest := uint64(s); rest != 0; rest &= rest - 1 {
+		sum += bits.TrailingZeros64(rest)
+	}
+	return sum
+}
+
+func (s ItemSet) String() string {
+	return string(s.Items())
+}
+
+// Items returns every item in the rucksack
+func (r Rucksack) Items() ItemSet {
+	return r.compartments[0].Union(r.compartments[1])
+}
+
+// Duplicates returns the items found in both compartments
+func (r Rucksack) Duplicates() ItemSet {
+	return r.compartments[0].Intersect(r.compartments[1])
+}
+
+func getTotalPriority(sacks []Rucksack) (sum int) {
+	for _, sack := range sacks {
+		sum += sack.Duplicates().Priority()
+	}
+
+	return
+}
+
+// getItemPriority returns 1-26 for a-z, 27-52 for A-Z, and 0 for anything
+// else
+func getItemPriority(c byte) int {
+	switch {
+	case c >= 'a' && c <= 'z':
+		{
+			return int(c-'a') + 1
+		}
+	case c >= 'A' && c <= 'Z':
+		{
+			return int(c-'A') + 27
+		}
+	default:
+		{
+			return 0
+		}
+	}
+}
+
+func itemWithPriority(p int) byte {
+	if p <= 26 {
+		return byte('a' + p - 1)
+	}
+	return byte('A' + p - 27)
+}
+
+// GroupRucksacks splits the sacks into groups of size, in order, and finds
+// each group's badge: the one item carried by every sack in the group
+func GroupRucksacks(sacks []Rucksack, size int) ([]RucksackGroup, error) {
+	if size <= 0 || size > 64 {
+		return nil, fmt.Errorf("invalid group size %d", size)
+	}
+	if len(sacks)%size != 0 {
+		return nil, fmt.Errorf("%d rucksacks can't be split into groups of %d", len(sacks), size)
+	}
+
+	groups := make([]RucksackGroup, len(sacks)/size)
+	for i := range groups {
+		group := RucksackGroup{sacks: sacks[i*size : (i+1)*size]}
+		common := group.Common()
+		if common.Len() != 1 {
+			return nil, parse.Errorf(group.sacks[0].line, 0, "the group starting here shares %d items (%s), not exactly one", common.Len(), common)
+		}
+		group.badgeItem = common.Items()[0]
+		solver.Debugf(solver.TRACE, "group %d: badge %c\n%s", i+1, group.badgeItem, group.SharingReport())
+		groups[i] = group
+	}
+
+	return groups, nil
+}
+
+// Badge returns the one item carried by every sack in the group
+func (g RucksackGroup) Badge() byte {
+	return g.badgeItem
+}
+
+// Lines returns the lines of the input the group's first and last sacks were
+// read from
+func (g RucksackGroup) Lines() (int, int) {
+	return g.sacks[0].line, g.sacks[len(g.sacks)-1].line
+}
+
+// Common returns the items carried by every sack in the group
+func (g RucksackGroup) Common() ItemSet {
+	common := ^ItemSet(0)
+	for _, sack := range g.sacks {
+		common = common.Intersect(sack.Items())
+	}
+	return common
+}
+
+// Sharing groups the items carried by the group according to exactly which
+// sacks carry them. Each key is a subset of the group's sacks, with bit i set
+// for the group's ith sack, so the key with every bit set maps to the badge.
+func (g RucksackGroup) Sharing() map[uint64]ItemSet {
+	sharing := make(map[uint64]ItemSet)
+	for p := 1; p <= 52; p++ {
+		item := ItemSet(1) << p
+		var subset uint64
+		for i, sack := range g.sacks {
+			if sack.Items()&item != 0 {
+				subset |= 1 << i
+			}
+		}
+		if subset != 0 {
+			sharing[subset] = sharing[subset].Union(item)
+		}
+	}
+	return sharing
+}
+
+// SharingReport lists the items shared by more than one sack in the group,
+// one line per subset of sacks, with the sacks numbered from 1
+func (g RucksackGroup) SharingReport() string {
+	sharing := g.Sharing()
+	subsets := make([]uint64, 0, len(sharing))
+	for subset := range sharing {
+		if bits.OnesCount64(subset) > 1 {
+			subsets = append(subsets, subset)
+		}
+	}
+	// Most widely shared first
+	sort.Slice(subsets, func(i, j int) bool {
+		ci, cj := bits.OnesCount64(subsets[i]), bits.OnesCount64(subsets[j])
+		if ci != cj {
+			return ci > cj
+		}
+		return subsets[i] < subsets[j]
+	})
+
+	var b strings.Builder
+	for _, subset := range subsets {
+		members := make([]string, 0)
+		for i := range g.sacks {
+			if subset&(1<<i) != 0 {
+				members = append(members, fmt.Sprint(i+1))
+			}
+		}
+		fmt.Fprintf(&b, "  sacks %s: %s\n", strings.Join(members, ","), sharing[subset])
+	}
+	return b.String()
+}
+
+// BadgePriority returns the total priority of the groups' badges
+func BadgePriority(groups []RucksackGroup) (sum int) {
+	for _, group := range groups {
+		sum += getItemPriority(group.badgeItem)
+	}
+
+	return
+}
@@ -0,0 +1,88 @@
+package day3
+
+import (
+	"strings"
+	"testing"
+)
+
+func readTestRucksacks(t *testing.T, input string) []Rucksack {
+	t.Helper()
+	sacks, err := ReadRucksacks(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sacks
+}
+
+func TestGroupsOfFour(t *testing.T) {
+	sacks := readTestRucksacks(t, "aXbc\naXde\nbXdf\ngXhi\n")
+	groups, err := GroupRucksacks(sacks, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("got %d groups, want 1", len(groups))
+	}
+	g := groups[0]
+	if g.Badge() != 'X' {
+		t.Errorf("got badge %c, want X", g.Badge())
+	}
+	if first, last := g.Lines(); first != 1 || last != 4 {
+		t.Errorf("got lines %d-%d, want 1-4", first, last)
+	}
+	if got := BadgePriority(groups); got != 50 {
+		t.Errorf("got badge priority %d, want 50", got)
+	}
+
+	want := "  sacks 1,2,3,4: X\n" +
+		"  sacks 1,2: a\n" +
+		"  sacks 1,3: b\n" +
+		"  sacks 2,3: d\n"
+	if got := g.SharingReport(); got != want {
+		t.Errorf("got report\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGroupsOfTwo(t *testing.T) {
+	sacks := readTestRucksacks(t, "abcd\naefg\nhijK\nKlmn\n")
+	groups, err := GroupRucksacks(sacks, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	badges := ""
+	for _, g := range groups {
+		badges += string(g.Badge())
+		if first, _ := g.Lines(); first%2 != 1 {
+			t.Errorf("group starts on line %d", first)
+		}
+		if got, want := g.SharingReport(), "  sacks 1,2: "+string(g.Badge())+"\n"; got != want {
+			t.Errorf("got report %q, want %q", got, want)
+		}
+	}
+	if badges != "aK" {
+		t.Errorf("got badges %q, want \"aK\"", badges)
+	}
+	if got := BadgePriority(groups); got != 1+37 {
+		t.Errorf("got badge priority %d, want %d", got, 1+37)
+	}
+}
+
+func TestGroupErrors(t *testing.T) {
+	sacks := readTestRucksacks(t, "aXbc\naXde\nbXdf\ngXhi\n")
+	tests := []struct {
+		name string
+		size int
+	}{
+		{"shares two items", 2},
+		{"doesn't divide", 3},
+		{"empty", 0},
+		{"too large", 65},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := GroupRucksacks(sacks, test.size); err == nil {
+				t.Errorf("grouped into %d without an error", test.size)
+			}
+		})
+	}
+}
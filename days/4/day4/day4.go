@@ -0,0 +1,141 @@
+package day4
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/FaideWW/aoc-2022/interval"
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+type RangePair struct {
+	ranges [2]interval.Interval
+	// The line of the input the pair was read from
+	line int
+}
+
+// Overlap describes the sections assigned to both elves in a pair
+type Overlap struct {
+	Line     int
+	Sections interval.Interval
+	// Whether one elf's sections contain all of the other's
+	Full bool
+}
+
+// Part1 returns the number of pairs where one range fully contains the other
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	pairs, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	overlaps := findOverlaps(pairs)
+	if solver.Verbosity >= solver.DEBUG {
+		for _, o := range overlaps {
+			solver.Debugf(solver.DEBUG, "%s\n", o)
+		}
+	}
+	fullOverlaps, _ := countOverlaps(overlaps)
+	return fmt.Sprint(fullOverlaps), nil
+}
+
+// Part2 returns the number of pairs that overlap at all
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	pairs, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	_, partialOverlaps := countOverlaps(findOverlaps(pairs))
+	return fmt.Sprint(partialOverlaps), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) ([]RangePair, error) {
+	lines := parse.Lines(input)
+	pairs := make([]RangePair, 0, len(lines))
+
+	for _, line := range lines {
+		if len(line.Text) == 0 {
+			continue
+		}
+		ranges, err := line.SplitN(",", 2, "two ranges like \"2-4,6-8\"")
+		if err != nil {
+			return nil, err
+		}
+		range1, err := parseRange(ranges[0])
+		if err != nil {
+			return nil, err
+		}
+		range2, err := parseRange(ranges[1])
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, RangePair{ranges: [2]interval.Interval{range1, range2}, line: line.Line})
+	}
+	return pairs, nil
+}
+
+func parseRange(input parse.Field) (interval.Interval, error) {
+	bounds, err := input.SplitN("-", 2, "a range like \"2-4\"")
+	if err != nil {
+		return interval.Empty, err
+	}
+	ends, err := parse.Ints(bounds)
+	if err != nil {
+		return interval.Empty, err
+	}
+	if ends[0] > ends[1] {
+		return interval.Empty, input.Errorf("range %q ends before it starts", input.Text)
+	}
+	return interval.Interval{Min: ends[0], Max: ends[1]}, nil
+}
+
+// findOverlaps returns the pairs whose ranges overlap, in input order
+func findOverlaps(pairs []RangePair) []Overlap {
+	overlaps := make([]Overlap, 0)
+	for _, pair := range pairs {
+		a, b := pair.ranges[0], pair.ranges[1]
+		shared := a.Intersect(b)
+		if shared.IsEmpty() {
+			continue
+		}
+		overlaps = append(overlaps, Overlap{
+			Line:     pair.line,
+			Sections: shared,
+			Full:     a.Covers(b) || b.Covers(a),
+		})
+	}
+	return overlaps
+}
+
+func countOverlaps(overlaps []Overlap) (fullOverlaps int, partialOverlaps int) {
+	for _, o := range overlaps {
+		if o.Full {
+			fullOverlaps++
+		}
+		partialOverlaps++
+	}
+
+	return
+}
+
+func (o Overlap) String() string {
+	kind := "partial"
+	if o.Full {
+		kind = "full"
+	}
+	return fmt.Sprintf("line %d: %s overlap of %d sections %s", o.Line, kind, o.Sections.Len(), o.Sections)
+}
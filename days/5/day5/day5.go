@@ -0,0 +1,376 @@
+package day5
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+type CargoLane []byte
+
+// Model is the crane used to move the crates
+type Model int
+
+const (
+	// Moves crates one at a time
+	CRATE_MOVER_9000 Model = 9000
+	// Moves a whole batch of crates at once, keeping their order
+	CRATE_MOVER_9001 Model = 9001
+)
+
+type Instruction struct {
+	amount int
+	from   int
+	to     int
+	// The line of the input the instruction was read from
+	line int
+}
+
+// operation is a batch of crates taken off the top of one lane and put, in
+// the same order, on top of another
+type operation struct {
+	from   int
+	to     int
+	crates []byte
+}
+
+type CargoState struct {
+	lanes              []CargoLane
+	instructions       []Instruction
+	instructionPointer int
+	model              Model
+	// The operations performed by each executed instruction, so that they can
+	// be undone
+	history [][]operation
+}
+
+// Part1 returns the top crate of each lane after moving crates one at a time
+func Part1(r io.Reader) (string, error) {
+	return simulate(r, CRATE_MOVER_9000)
+}
+
+// Part2 returns the top crate of each lane after moving crates in batches
+func Part2(r io.Reader) (string, error) {
+	return simulate(r, CRATE_MOVER_9001)
+}
+
+func simulate(r io.Reader, model Model) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	state, err := NewCargoState(input, model)
+	if err != nil {
+		return "", err
+	}
+	if err := state.RunTo(state.Len()); err != nil {
+		return "", err
+	}
+	return state.TopCrates(), nil
+}
+
+// NewCargoState reads the crate diagram and instructions, ready to be stepped
+// through with the given crane model
+func NewCargoState(input string, model Model) (*CargoState, error) {
+	if model != CRATE_MOVER_9000 && model != CRATE_MOVER_9001 {
+		return nil, fmt.Errorf("unknown crane model %d", model)
+	}
+	state, err := parseInput(input)
+	if err != nil {
+		return nil, err
+	}
+	state.model = model
+	return &state, nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) (CargoState, error) {
+	lines := parse.Lines(input)
+	split := -1
+	for i, line := range lines {
+		if len(line.Text) == 0 {
+			split = i
+			break
+		}
+	}
+	if split < 1 {
+		return CargoState{}, parse.Errorf(1, 0, "expected a crate diagram followed by a blank line")
+	}
+
+	lanes, err := parseCrates(lines[:split])
+	if err != nil {
+		return CargoState{}, err
+	}
+	instructions, err := parseInstructions(lines[split+1:], len(lanes))
+	if err != nil {
+		return CargoState{}, err
+	}
+
+	return CargoState{
+		lanes:              lanes,
+		instructions:       instructions,
+		instructionPointer: 0,
+	}, nil
+}
+
+func parseCrates(input []parse.Field) ([]CargoLane, error) {
+	// the last line of the diagram numbers the lanes
+	labels := input[len(input)-1]
+	numLanes := len(labels.Fields())
+	if numLanes == 0 {
+		return nil, labels.Errorf("expected the lanes to be numbered under the crates")
+	}
+	for lane, label := range labels.Fields() {
+		n, err := label.Int()
+		if err != nil {
+			return nil, err
+		}
+		if n != lane+1 {
+			return nil, label.Errorf("expected lane %d, got %d", lane+1, n)
+		}
+	}
+	stackSize := len(input) - 1
+
+	lanes := make([]CargoLane, numLanes)
+
+	for i := 0; i < numLanes; i++ {
+		lane := make(CargoLane, 0)
+		lanes[i] = lane
+	}
+
+	// Fill the lanes from the bottom up
+	for i := stackSize - 1; i >= 0; i-- {
+		height := (stackSize - 1) - i
+		line := input[i]
+		if len(line.Text) > numLanes*4 {
+			return nil, parse.Errorf(line.Line, numLanes*4+1, "crate outside of the %d lanes", numLanes)
+		}
+		for lane := 0; lane < numLanes; lane++ {
+			cratePointer := lane * 4
+			if cratePointer >= len(line.Text) || line.Text[cratePointer] == ' ' {
+				// trailing spaces may have been trimmed from the line
+				continue
+			}
+			if cratePointer+2 >= len(line.Text) || line.Text[cratePointer] != '[' || line.Text[cratePointer+2] != ']' {
+				return nil, parse.Errorf(line.Line, line.Col+cratePointer, "expected a crate like \"[A]\"")
+			}
+			crate := line.Text[cratePointer+1]
+			if len(lanes[lane]) != height {
+				return nil, parse.Errorf(line.Line, line.Col+cratePointer, "crate %c is floating in lane %d", crate, lane+1)
+			}
+			lanes[lane] = append(lanes[lane], crate)
+			solver.Debugf(solver.TRACE, "Crate %s at height %d in lane %d\n", string(crate), height, lane)
+		}
+	}
+
+	return lanes, nil
+}
+
+func parseInstructions(input []parse.Field, numLanes int) ([]Instruction, error) {
+	r := regexp.MustCompile(`move (\d+) from (\d+) to (\d+)`)
+	instructions := make([]Instruction, 0, len(input))
+
+	for _, line := range input {
+		if len(line.Text) == 0 {
+			continue
+		}
+		data, err := line.Match(r, "an instruction like \"move 1 from 2 to 3\"")
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := parse.Ints(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, lane := range data[1:] {
+			if n, _ := lane.Int(); n < 1 || n > numLanes {
+				return nil, lane.Errorf("no lane %d", n)
+			}
+		}
+
+		instructions = append(instructions, Instruction{amount: values[0], from: values[1], to: values[2], line: line.Line})
+	}
+
+	return instructions, nil
+}
+
+// Len returns the number of instructions
+func (s *CargoState) Len() int {
+	return len(s.instructions)
+}
+
+// Position returns the number of instructions executed so far
+func (s *CargoState) Position() int {
+	return s.instructionPointer
+}
+
+func (s *CargoState) hasNextInstruction() bool {
+	return s.instructionPointer < len(s.instructions)
+}
+
+// Next returns the next instruction to be executed, in the form it was written
+// in the input, and false if there are none left
+func (s *CargoState) Next() (string, bool) {
+	if !s.hasNextInstruction() {
+		return "", false
+	}
+	return s.instructions[s.instructionPointer].String(), true
+}
+
+// Step executes the next instruction. Moving more crates than a lane holds is
+// reported against the instruction's line, and leaves the lanes untouched.
+func (s *CargoState) Step() error {
+	if !s.hasNextInstruction() {
+		return errors.New("no instructions left")
+	}
+
+	instruction := s.instructions[s.instructionPointer]
+	if available := len(s.lanes[instruction.from-1]); available < instruction.amount {
+		return parse.Errorf(instruction.line, 0, "%s: lane %d only has %d crates", instruction, instruction.from, available)
+	}
+	solver.Debugf(solver.TRACE, "%s\n", instruction)
+
+	var ops []operation
+	if s.model == CRATE_MOVER_9001 {
+		ops = []operation{s.move(instruction.from, instruction.to, instruction.amount)}
+	} else {
+		ops = make([]operation, instruction.amount)
+		for i := range ops {
+			ops[i] = s.move(instruction.from, instruction.to, 1)
+		}
+	}
+
+	s.history = append(s.history, ops)
+	s.instructionPointer++
+	return nil
+}
+
+// Back undoes the last executed instruction, returning false if there is
+// nothing to undo
+func (s *CargoState) Back() bool {
+	if len(s.history) == 0 {
+		return false
+	}
+
+	ops := s.history[len(s.history)-1]
+	for i := len(ops) - 1; i >= 0; i-- {
+		s.move(ops[i].to, ops[i].from, len(ops[i].crates))
+	}
+
+	s.history = s.history[:len(s.history)-1]
+	s.instructionPointer--
+	return true
+}
+
+// RunTo steps forwards or backwards until exactly n instructions have been
+// executed
+func (s *CargoState) RunTo(n int) error {
+	if n < 0 || n > len(s.instructions) {
+		return fmt.Errorf("there are only %d instructions", len(s.instructions))
+	}
+	for s.instructionPointer > n {
+		s.Back()
+	}
+	for s.instructionPointer < n {
+		if err := s.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// move takes amount crates off the top of one lane and puts them on another,
+// keeping their order. The caller checks there are enough crates.
+func (s *CargoState) move(from int, to int, amount int) operation {
+	crates := s.lanes[from-1].popMany(amount)
+	s.lanes[to-1].pushMany(crates)
+	return operation{from: from, to: to, crates: crates}
+}
+
+func (l *CargoLane) pushMany(arr []byte) {
+	*l = append(*l, arr...)
+}
+
+// popMany removes the top amount crates, returning them bottom first
+func (l *CargoLane) popMany(amount int) []byte {
+	newLast := len(*l) - amount
+	crates := make([]byte, amount)
+	copy(crates, (*l)[newLast:])
+	*l = (*l)[:newLast]
+
+	return crates
+}
+
+// TopCrates returns the crate on top of each lane, with a space for empty
+// lanes
+func (s *CargoState) TopCrates() string {
+	crates := ""
+	for _, lane := range s.lanes {
+		if len(lane) == 0 {
+			crates += " "
+			continue
+		}
+		crates += string(lane[len(lane)-1])
+	}
+
+	return crates
+}
+
+// Render draws the lanes exactly as they are drawn in the puzzle input, with
+// every row padded to the full width and the lanes numbered underneath
+func (s *CargoState) Render() string {
+	height := 0
+	for _, lane := range s.lanes {
+		if len(lane) > height {
+			height = len(lane)
+		}
+	}
+
+	var b strings.Builder
+	row := make([]string, len(s.lanes))
+	for y := height - 1; y >= 0; y-- {
+		for i, lane := range s.lanes {
+			row[i] = "   "
+			if y < len(lane) {
+				// Copy the byte as it is, since %c would encode it as a rune
+				row[i] = "[" + string(lane[y:y+1]) + "]"
+			}
+		}
+		b.WriteString(strings.Join(row, " "))
+		b.WriteByte('\n')
+	}
+	for i := range s.lanes {
+		row[i] = fmt.Sprintf(" %-2d", i+1)
+	}
+	b.WriteString(strings.Join(row, " "))
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// Input returns a puzzle input that starts from the current lanes and carries
+// out the instructions that haven't been executed yet. Parsing it gives back
+// an identical state, rewound to the start.
+func (s *CargoState) Input() string {
+	var b strings.Builder
+	b.WriteString(s.Render())
+	b.WriteByte('\n')
+	for _, instruction := range s.instructions[s.instructionPointer:] {
+		b.WriteString(instruction.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func (i Instruction) String() string {
+	return fmt.Sprintf("move %d from %d to %d", i.amount, i.from, i.to)
+}
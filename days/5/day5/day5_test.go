@@ -0,0 +1,102 @@
+package day5
+
+import (
+	"math/rand"
+	"os"
+	"testing"
+)
+
+// sameState compares the lanes and remaining instructions, ignoring where the
+// instructions were read from
+func sameState(a *CargoState, b *CargoState) bool {
+	if len(a.lanes) != len(b.lanes) || a.Len()-a.Position() != b.Len()-b.Position() {
+		return false
+	}
+	for i := range a.lanes {
+		if string(a.lanes[i]) != string(b.lanes[i]) {
+			return false
+		}
+	}
+	for i := 0; i < a.Len()-a.Position(); i++ {
+		x, y := a.instructions[a.Position()+i], b.instructions[b.Position()+i]
+		if x.amount != y.amount || x.from != y.from || x.to != y.to {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRenderMatchesInput(t *testing.T) {
+	dat, err := os.ReadFile("../test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := NewCargoState(string(dat), CRATE_MOVER_9000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.Input(); got != string(dat) {
+		t.Errorf("Input() =\n%s\nwant\n%s", got, dat)
+	}
+}
+
+func TestGeneratedRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		state := Generate(rng, rng.Intn(12)+1, rng.Intn(40)+1, rng.Intn(30))
+		input := state.Input()
+		parsed, err := NewCargoState(input, CRATE_MOVER_9000)
+		if err != nil {
+			t.Fatalf("can't parse generated puzzle: %s\n%s", err, input)
+		}
+		if !sameState(state, parsed) {
+			t.Fatalf("puzzle changed after a round trip:\n%s\nbecame\n%s", input, parsed.Input())
+		}
+
+		// Every generated instruction can be carried out by both cranes
+		for _, model := range []Model{CRATE_MOVER_9000, CRATE_MOVER_9001} {
+			run, _ := NewCargoState(input, model)
+			if err := run.RunTo(run.Len()); err != nil {
+				t.Fatalf("generated puzzle fails with the %d: %s\n%s", model, err, input)
+			}
+		}
+	}
+}
+
+func TestRoundTripMidway(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	state := Generate(rng, 5, 20, 10)
+	if err := state.RunTo(4); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := NewCargoState(state.Input(), CRATE_MOVER_9000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameState(state, parsed) || parsed.Len() != 6 {
+		t.Fatalf("got\n%s\nwant\n%s", parsed.Input(), state.Input())
+	}
+}
+
+// FuzzParse checks that any input the parser accepts is rendered back to an
+// input that parses to the same state
+func FuzzParse(f *testing.F) {
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 10; i++ {
+		f.Add(Generate(rng, rng.Intn(9)+1, rng.Intn(30)+1, rng.Intn(10)).Input())
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		state, err := NewCargoState(input, CRATE_MOVER_9000)
+		if err != nil {
+			return
+		}
+		rendered := state.Input()
+		parsed, err := NewCargoState(rendered, CRATE_MOVER_9000)
+		if err != nil {
+			t.Fatalf("can't parse rendered puzzle: %s\n%s", err, rendered)
+		}
+		if !sameState(state, parsed) {
+			t.Fatalf("puzzle changed after a round trip:\n%s\nbecame\n%s", rendered, parsed.Input())
+		}
+	})
+}
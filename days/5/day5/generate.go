@@ -0,0 +1,57 @@
+package day5
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Generate returns a random puzzle with numCrates crates, labelled A-Z,
+// stacked across numLanes lanes, followed by numInstructions instructions that
+// can all be carried out by either crane. Instructions never move crates onto
+// the lane they came from unless there is only one lane.
+func Generate(rng *rand.Rand, numLanes int, numCrates int, numInstructions int) *CargoState {
+	if numLanes < 1 {
+		panic(fmt.Sprintf("day5: can't generate a puzzle with %d lanes", numLanes))
+	}
+	if numCrates < 1 && numInstructions > 0 {
+		panic("day5: can't generate instructions without any crates")
+	}
+
+	lanes := make([]CargoLane, numLanes)
+	for i := range lanes {
+		lanes[i] = make(CargoLane, 0)
+	}
+	for i := 0; i < numCrates; i++ {
+		lane := rng.Intn(numLanes)
+		lanes[lane] = append(lanes[lane], byte('A'+rng.Intn(26)))
+	}
+
+	// Both cranes leave the same number of crates in each lane, so only the
+	// lane sizes need to be tracked to keep every instruction possible
+	sizes := make([]int, numLanes)
+	for i, lane := range lanes {
+		sizes[i] = len(lane)
+	}
+	instructions := make([]Instruction, numInstructions)
+	for i := range instructions {
+		from := rng.Intn(numLanes)
+		for sizes[from] == 0 {
+			from = rng.Intn(numLanes)
+		}
+		to := from
+		for to == from && numLanes > 1 {
+			to = rng.Intn(numLanes)
+		}
+		amount := rng.Intn(sizes[from]) + 1
+		sizes[from] -= amount
+		sizes[to] += amount
+
+		instructions[i] = Instruction{amount: amount, from: from + 1, to: to + 1}
+	}
+
+	return &CargoState{
+		lanes:        lanes,
+		instructions: instructions,
+		model:        CRATE_MOVER_9000,
+	}
+}
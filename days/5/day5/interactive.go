@@ -0,0 +1,99 @@
+package day5
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const STEP_HELP = `commands:
+  s, step [n]    execute the next n moves (default 1)
+  b, back [n]    undo the last n moves (default 1)
+  g, goto <n>    run or undo until n moves have been executed
+  r, run         execute every remaining move
+  q, quit        stop
+`
+
+// Interactive reads commands from in, stepping through the crate moves and
+// drawing the lanes to out after each command
+func Interactive(state *CargoState, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	fmt.Fprint(out, STEP_HELP)
+	for {
+		fmt.Fprintf(out, "\n%s", state.Render())
+		if next, ok := state.Next(); ok {
+			fmt.Fprintf(out, "%d/%d moves done, next: %s\n> ", state.Position(), state.Len(), next)
+		} else {
+			fmt.Fprintf(out, "all %d moves done, top crates: %s\n> ", state.Len(), state.TopCrates())
+		}
+
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			fields = []string{"step"}
+		}
+
+		count := 1
+		if len(fields) > 1 {
+			n, err := strconv.Atoi(fields[1])
+			if err != nil || n < 0 {
+				fmt.Fprintf(out, "invalid number %q\n", fields[1])
+				continue
+			}
+			count = n
+		}
+
+		var err error
+		switch fields[0] {
+		case "s", "step":
+			{
+				err = state.RunTo(minInt(state.Position()+count, state.Len()))
+			}
+		case "b", "back":
+			{
+				err = state.RunTo(maxInt(state.Position()-count, 0))
+			}
+		case "g", "goto":
+			{
+				if len(fields) < 2 {
+					fmt.Fprintln(out, "goto needs a number of moves")
+					continue
+				}
+				err = state.RunTo(count)
+			}
+		case "r", "run":
+			{
+				err = state.RunTo(state.Len())
+			}
+		case "q", "quit":
+			{
+				return nil
+			}
+		default:
+			{
+				fmt.Fprint(out, STEP_HELP)
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(out, err)
+		}
+	}
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,181 @@
+package day6
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+const PACKET_MARKER_LENGTH int = 4
+const MESSAGE_MARKER_LENGTH int = 14
+
+// ErrStopScan can be returned by the function passed to ScanMarkers to stop
+// reading the datastream early
+var ErrStopScan = errors.New("stop scanning")
+
+// Marker is a position in the datastream where the last Size characters are
+// all different
+type Marker struct {
+	Size int
+	// The number of characters read, including the marker itself
+	Offset int
+}
+
+// window keeps count of the characters in the last size characters read
+type window struct {
+	size   int
+	counts [256]int
+	// The number of characters that appear more than once in the window
+	repeats int
+}
+
+// Part1 returns the position of the first start-of-packet marker
+func Part1(r io.Reader) (string, error) {
+	markers, err := FirstMarkers(r, []int{PACKET_MARKER_LENGTH})
+	if err != nil {
+		return "", err
+	}
+	marker, ok := markers[PACKET_MARKER_LENGTH]
+	if !ok {
+		return "", errors.New("no start-of-packet marker in the datastream")
+	}
+	return fmt.Sprint(marker), nil
+}
+
+// Part2 returns the position of the first start-of-message marker
+func Part2(r io.Reader) (string, error) {
+	markers, err := FirstMarkers(r, []int{MESSAGE_MARKER_LENGTH})
+	if err != nil {
+		return "", err
+	}
+	marker, ok := markers[MESSAGE_MARKER_LENGTH]
+	if !ok {
+		return "", errors.New("no start-of-message marker in the datastream")
+	}
+	return fmt.Sprint(marker), nil
+}
+
+// ScanMarkers streams the datastream, a single line of lowercase letters,
+// calling fn with every marker of each of the given sizes as soon as its last
+// character is read. Markers ending at the same character are reported in the
+// order their sizes were given. Each character is only looked at once per
+// size, so the whole stream is scanned in linear time without being held in
+// memory.
+func ScanMarkers(r io.Reader, sizes []int, fn func(Marker) error) error {
+	if len(sizes) == 0 {
+		return errors.New("no marker sizes given")
+	}
+	windows := make([]window, len(sizes))
+	longest := 0
+	for i, size := range sizes {
+		if size < 1 {
+			return fmt.Errorf("invalid marker size %d", size)
+		}
+		windows[i].size = size
+		if size > longest {
+			longest = size
+		}
+	}
+
+	// The last longest characters read, indexed by their offset
+	recent := make([]byte, longest)
+	reader := bufio.NewReaderSize(r, 1<<16)
+	read := 0
+	for {
+		c, err := reader.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if c == '\n' || c == '\r' {
+			return expectEnd(reader, c)
+		}
+		if c < 'a' || c > 'z' {
+			return parse.Errorf(1, read+1, "unexpected character %q", c)
+		}
+
+		for i := range windows {
+			w := &windows[i]
+			if read >= w.size {
+				w.remove(recent[(read-w.size)%longest])
+			}
+			w.add(c)
+			if read+1 >= w.size && w.repeats == 0 {
+				err := fn(Marker{Size: w.size, Offset: read + 1})
+				if err == ErrStopScan {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		recent[read%longest] = c
+		read++
+	}
+}
+
+// expectEnd checks nothing but the line ending follows the datastream
+func expectEnd(reader *bufio.Reader, c byte) error {
+	if c == '\r' {
+		next, err := reader.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if next != '\n' {
+			return parse.Errorf(2, 0, "expected a single line")
+		}
+	}
+	if _, err := reader.ReadByte(); err != io.EOF {
+		if err != nil {
+			return err
+		}
+		return parse.Errorf(2, 0, "expected a single line")
+	}
+	return nil
+}
+
+// FirstMarkers returns the offset of the first marker of each size, stopping
+// as soon as all of them have been found. Sizes without a marker are missing
+// from the result.
+func FirstMarkers(r io.Reader, sizes []int) (map[int]int, error) {
+	first := make(map[int]int)
+	wanted := make(map[int]bool)
+	for _, size := range sizes {
+		wanted[size] = true
+	}
+
+	err := ScanMarkers(r, sizes, func(m Marker) error {
+		if _, ok := first[m.Size]; !ok {
+			first[m.Size] = m.Offset
+		}
+		if len(first) == len(wanted) {
+			return ErrStopScan
+		}
+		return nil
+	})
+	return first, err
+}
+
+func (w *window) add(c byte) {
+	w.counts[c]++
+	if w.counts[c] == 2 {
+		w.repeats++
+	}
+}
+
+func (w *window) remove(c byte) {
+	w.counts[c]--
+	if w.counts[c] == 1 {
+		w.repeats--
+	}
+}
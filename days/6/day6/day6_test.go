@@ -0,0 +1,91 @@
+package day6
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// bruteForce checks every window of every size separately
+func bruteForce(input string, sizes []int) []Marker {
+	markers := make([]Marker, 0)
+	for end := 1; end <= len(input); end++ {
+		for _, size := range sizes {
+			if end < size {
+				continue
+			}
+			seen := make(map[byte]bool)
+			for i := end - size; i < end; i++ {
+				seen[input[i]] = true
+			}
+			if len(seen) == size {
+				markers = append(markers, Marker{Size: size, Offset: end})
+			}
+		}
+	}
+	return markers
+}
+
+func TestScanMarkersMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	sizes := []int{4, 14, 1, 3}
+	for i := 0; i < 100; i++ {
+		// A small alphabet keeps markers rare enough to be interesting
+		alphabet := rng.Intn(20) + 2
+		b := make([]byte, rng.Intn(200))
+		for j := range b {
+			b[j] = byte('a' + rng.Intn(alphabet))
+		}
+		input := string(b)
+
+		got := make([]Marker, 0)
+		err := ScanMarkers(strings.NewReader(input+"\n"), sizes, func(m Marker) error {
+			got = append(got, m)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := bruteForce(input, sizes); !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestFirstMarkers(t *testing.T) {
+	input := "zcfzfwzzqfrljwzlrfnpqdbhtmscgvjw"
+	got, err := FirstMarkers(strings.NewReader(input), []int{4, 14, 33})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int]int{4: 11, 14: 26}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMarkerAtEnd(t *testing.T) {
+	// The only marker is the very last window
+	got, err := FirstMarkers(strings.NewReader("aaaabcd"), []int{4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[4] != 7 {
+		t.Errorf("got %v, want a marker at 7", got)
+	}
+}
+
+func TestExtraLines(t *testing.T) {
+	for _, input := range []string{"abcd\n\n", "abcd\nefgh", "abcd\r\nx", "abcd\rx"} {
+		_, err := FirstMarkers(strings.NewReader(input), []int{14})
+		if err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+	for _, input := range []string{"abcd", "abcd\n", "abcd\r\n"} {
+		if _, err := FirstMarkers(strings.NewReader(input), []int{14}); err != nil {
+			t.Errorf("%q: %s", input, err)
+		}
+	}
+}
@@ -1,51 +1,66 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
-)
-
-func check(e error) {
-	if e != nil {
-		panic(e)
-	}
-}
 
-const MAX_LENGTH int = 14
+	"github.com/FaideWW/aoc-2022/days/6/day6"
+	"github.com/FaideWW/aoc-2022/solver"
+)
 
 func main() {
-	input := strings.TrimSpace(readInputFile(os.Args[1]))
-	fmt.Println(input)
-
-	var marker int
-	for start := 0; start < len(input)-MAX_LENGTH; start++ {
-		substr := input[start : start+MAX_LENGTH]
-		fmt.Printf("testing %s\n", substr)
-		if allUnique(substr) {
-			marker = start + MAX_LENGTH
-			break
-		}
+	sizes := flag.String("sizes", "", "print every marker of these comma-separated sizes, such as 4,14")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [--sizes 4,14] <input file>\n", os.Args[0])
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	fmt.Printf("first marker at %d\n", marker)
-
-}
+	if *sizes == "" {
+		solver.Main(solver.Day{Part1: day6.Part1, Part2: day6.Part2})
+		return
+	}
 
-func readInputFile(filename string) string {
-	dat, err := os.ReadFile(filename)
-	check(err)
-	return string(dat)
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *sizes); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
 
-func allUnique(str string) bool {
-	seen := make(map[rune]bool)
-	for _, r := range str {
-		if seen[r] {
-			return false
+// run prints the markers as they are found, so the datastream never has to
+// fit in memory
+func run(path string, sizeList string) error {
+	sizes := make([]int, 0)
+	for _, s := range strings.Split(sizeList, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("invalid marker size %q", s)
 		}
-		seen[r] = true
+		sizes = append(sizes, size)
 	}
 
-	return true
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	err = day6.ScanMarkers(f, sizes, func(m day6.Marker) error {
+		_, err := fmt.Fprintf(out, "%d\t%d\n", m.Size, m.Offset)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
 }
@@ -0,0 +1,589 @@
+package day7
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+type Instruction struct {
+	command  string
+	arg      parse.Field
+	line     parse.Field
+	response []parse.Field
+}
+
+type File struct {
+	name string
+	size int
+	// The line of the listing the file was first seen in
+	line int
+}
+
+type Directory struct {
+	name   string
+	parent *Directory
+	// The cached total size, or -1 if it has to be worked out again
+	size           int
+	subdirectories map[string]*Directory
+	files          map[string]*File
+	// The line of the listing the directory was first seen in, which is 0 for
+	// the root
+	line int
+	// The line the directory's contents were first listed at, or 0 if they
+	// never were
+	listedAt int
+}
+
+const MAX_SIZE = 100000
+
+const DISK_SIZE = 70000000
+const FREE_SPACE_NEEDED = 30000000
+
+// Disk describes the device the filesystem is stored on
+type Disk struct {
+	Capacity int
+	// The free space needed to install the update
+	Needed int
+}
+
+// DefaultDisk is the device described by the puzzle
+var DefaultDisk = Disk{Capacity: DISK_SIZE, Needed: FREE_SPACE_NEEDED}
+
+// Part1 returns the total size of all directories smaller than MAX_SIZE
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	fs, err := readFileSystem(input)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := getDirectoriesSmallerThan(fs, MAX_SIZE)
+	return fmt.Sprint(sumDirectorySizes(candidates)), nil
+}
+
+// Part2 returns the size of the smallest directory that frees up enough space
+// for the update
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	fs, err := readFileSystem(input)
+	if err != nil {
+		return "", err
+	}
+
+	dirToDelete, err := FindDirectoryToDelete(fs, DefaultDisk)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(dirToDelete.Size()), nil
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+// ReadFileSystem replays a transcript of cd and ls commands, returning the
+// root of the filesystem they explored
+func ReadFileSystem(r io.Reader) (*Directory, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return nil, err
+	}
+	return readFileSystem(input)
+}
+
+func readFileSystem(input string) (*Directory, error) {
+	instructions, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return nil, err
+	}
+	return buildFileSystem(instructions)
+}
+
+func parseInput(input string) ([]Instruction, error) {
+	instructions := make([]Instruction, 0)
+
+	for _, line := range parse.Lines(input) {
+		if strings.HasPrefix(line.Text, "$") {
+			fields := line.Fields()
+			if len(fields) < 2 {
+				return nil, line.Errorf("expected a command after \"$\"")
+			}
+			instruction := Instruction{
+				command:  fields[1].Text,
+				line:     line,
+				response: make([]parse.Field, 0),
+			}
+			switch instruction.command {
+			case "cd":
+				{
+					if len(fields) != 3 {
+						return nil, line.Errorf("expected \"$ cd <directory>\", got %q", line.Text)
+					}
+					instruction.arg = fields[2]
+				}
+			case "ls":
+				{
+					if len(fields) != 2 {
+						return nil, fields[2].Errorf("ls takes no arguments")
+					}
+				}
+			default:
+				{
+					return nil, fields[1].Errorf("unknown command %q", instruction.command)
+				}
+			}
+			instructions = append(instructions, instruction)
+			continue
+		}
+
+		if len(instructions) == 0 {
+			return nil, line.Errorf("expected the transcript to start with a command")
+		}
+		last := &instructions[len(instructions)-1]
+		if last.command != "ls" {
+			return nil, line.Errorf("unexpected output from %q", last.line.Text)
+		}
+		last.response = append(last.response, line)
+	}
+
+	return instructions, nil
+}
+
+func makeDirectory(name string, parent *Directory, line int) *Directory {
+	return &Directory{
+		name:           name,
+		parent:         parent,
+		size:           -1,
+		subdirectories: map[string]*Directory{},
+		files:          map[string]*File{},
+		line:           line,
+	}
+}
+
+// entry is a line of ls output: either a directory, or a file with a size
+type entry struct {
+	name  string
+	isDir bool
+	size  int
+	line  parse.Field
+}
+
+func parseEntry(input parse.Field) (entry, error) {
+	fields, err := input.SplitN(" ", 2, "an entry like \"dir a\" or \"1234 name.txt\"")
+	if err != nil {
+		return entry{}, err
+	}
+	e := entry{name: strings.TrimSpace(fields[1].Text), line: input}
+	if e.name == "" || e.name == "." || e.name == ".." || strings.Contains(e.name, "/") {
+		return entry{}, fields[1].Errorf("invalid name %q", e.name)
+	}
+	if fields[0].Text == "dir" {
+		e.isDir = true
+		return e, nil
+	}
+
+	e.size, err = fields[0].Int()
+	if err != nil {
+		return entry{}, err
+	}
+	if e.size < 0 {
+		return entry{}, fields[0].Errorf("negative file size %d", e.size)
+	}
+	return e, nil
+}
+
+// buildFileSystem replays the transcript. Every cd has to lead somewhere
+// already seen in a listing, and listing a directory again has to show exactly
+// what it showed the first time, so a transcript that doesn't add up is
+// reported rather than producing the wrong sizes.
+func buildFileSystem(instructions []Instruction) (*Directory, error) {
+	root := makeDirectory("/", nil, 0)
+
+	currDir := root
+
+	for _, instruction := range instructions {
+		switch instruction.command {
+		case "cd":
+			{
+				dir, err := currDir.cd(instruction.arg)
+				if err != nil {
+					return nil, err
+				}
+				currDir = dir
+			}
+		case "ls":
+			{
+				if err := currDir.list(instruction); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			{
+				return nil, instruction.line.Errorf("unknown command %q", instruction.command)
+			}
+		}
+	}
+
+	if unlisted := root.findUnlisted(); unlisted != nil {
+		return nil, parse.Errorf(unlisted.line, 0, "%s is never listed, so the sizes of the directories containing it are unknown", unlisted.Path())
+	}
+
+	return root, nil
+}
+
+// cd follows the path from d, which may be absolute and may have several
+// parts, as the transcript's cd command would
+func (d *Directory) cd(path parse.Field) (*Directory, error) {
+	dir := d
+	if strings.HasPrefix(path.Text, "/") {
+		dir = d.root()
+	}
+	for _, name := range strings.Split(path.Text, "/") {
+		switch name {
+		case "", ".":
+			{
+				continue
+			}
+		case "..":
+			{
+				if dir.parent == nil {
+					return nil, path.Errorf("can't leave the root directory")
+				}
+				dir = dir.parent
+			}
+		default:
+			{
+				next, ok := dir.subdirectories[name]
+				switch {
+				case ok:
+					{
+						dir = next
+					}
+				case dir.files[name] != nil:
+					{
+						return nil, path.Errorf("%s is a file, listed at line %d", dir.child(name), dir.files[name].line)
+					}
+				case dir.listedAt == 0:
+					{
+						return nil, path.Errorf("can't find %s, since %s hasn't been listed yet", dir.child(name), dir.Path())
+					}
+				default:
+					{
+						return nil, path.Errorf("%s has no directory %q when listed at line %d", dir.Path(), name, dir.listedAt)
+					}
+				}
+			}
+		}
+	}
+	return dir, nil
+}
+
+// list records the output of ls in d. The first listing fills the directory
+// in, and any later one has to match it.
+func (d *Directory) list(instruction Instruction) error {
+	entries := make(map[string]entry, len(instruction.response))
+	order := make([]string, 0, len(instruction.response))
+	for _, line := range instruction.response {
+		e, err := parseEntry(line)
+		if err != nil {
+			return err
+		}
+		if earlier, ok := entries[e.name]; ok {
+			return line.Errorf("%s is listed twice, first at line %d", d.child(e.name), earlier.line.Line)
+		}
+		entries[e.name] = e
+		order = append(order, e.name)
+	}
+
+	if d.listedAt != 0 {
+		return d.checkListing(instruction, entries)
+	}
+
+	for _, name := range order {
+		e := entries[name]
+		if e.isDir {
+			d.subdirectories[name] = makeDirectory(name, d, e.line.Line)
+		} else {
+			d.files[name] = &File{name: name, size: e.size, line: e.line.Line}
+		}
+	}
+	d.listedAt = instruction.line.Line
+	d.invalidate()
+	return nil
+}
+
+// checkListing compares a repeated listing of d to the first one
+func (d *Directory) checkListing(instruction Instruction, entries map[string]entry) error {
+	for _, e := range entries {
+		file, isFile := d.files[e.name]
+		_, isDir := d.subdirectories[e.name]
+		switch {
+		case e.isDir && isFile:
+			{
+				return e.line.Errorf("%s is listed as a directory, but was a file at line %d", d.child(e.name), file.line)
+			}
+		case !e.isDir && isDir:
+			{
+				return e.line.Errorf("%s is listed as a file, but was a directory at line %d", d.child(e.name), d.listedAt)
+			}
+		case !e.isDir && isFile && file.size != e.size:
+			{
+				return e.line.Errorf("%s is listed with size %d, but had size %d at line %d", d.child(e.name), e.size, file.size, file.line)
+			}
+		case !isFile && !isDir:
+			{
+				return e.line.Errorf("%s wasn't there when %s was listed at line %d", d.child(e.name), d.Path(), d.listedAt)
+			}
+		}
+	}
+
+	for _, name := range d.names() {
+		if _, ok := entries[name]; !ok {
+			return instruction.line.Errorf("%s is missing %s, which was there when it was listed at line %d", d.Path(), name, d.listedAt)
+		}
+	}
+	return nil
+}
+
+// names returns the names of everything in the directory, sorted
+func (d *Directory) names() []string {
+	names := make([]string, 0, len(d.subdirectories)+len(d.files))
+	for name := range d.subdirectories {
+		names = append(names, name)
+	}
+	for name := range d.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// findUnlisted returns the first directory, in path order, whose contents
+// were never listed
+func (d *Directory) findUnlisted() *Directory {
+	if d.listedAt == 0 {
+		return d
+	}
+	for _, dir := range d.sortedSubdirectories() {
+		if unlisted := dir.findUnlisted(); unlisted != nil {
+			return unlisted
+		}
+	}
+	return nil
+}
+
+// invalidate forgets the cached sizes of d and every directory containing it
+func (d *Directory) invalidate() {
+	for dir := d; dir != nil; dir = dir.parent {
+		dir.size = -1
+	}
+}
+
+// child returns the path of something in the directory
+func (d *Directory) child(name string) string {
+	return strings.TrimSuffix(d.Path(), "/") + "/" + name
+}
+
+// printDirectoryTree writes the tree in the same style as the puzzle
+// description, with everything in a directory sorted by name
+func printDirectoryTree(w io.Writer, root *Directory, prefix string) {
+	fmt.Fprintf(w, "%s- %s (dir, size=%d)\n", prefix, root.name, root.Size())
+	for _, dir := range root.sortedSubdirectories() {
+		printDirectoryTree(w, dir, prefix+"  ")
+	}
+	for _, file := range root.sortedFiles() {
+		fmt.Fprintf(w, "%s  - %s (file, size=%d)\n", prefix, file.name, file.size)
+	}
+}
+
+func (d *Directory) Name() string {
+	return d.name
+}
+
+// Path returns the absolute path of the directory
+func (d *Directory) Path() string {
+	if d.parent == nil {
+		return "/"
+	}
+	parent := d.parent.Path()
+	if !strings.HasSuffix(parent, "/") {
+		parent += "/"
+	}
+	return parent + d.name
+}
+
+func (d *Directory) root() *Directory {
+	for d.parent != nil {
+		d = d.parent
+	}
+	return d
+}
+
+// Lookup finds the directory at path, which is either absolute or relative to
+// d. As in a shell, ".." from the root stays at the root.
+func (d *Directory) Lookup(path string) (*Directory, error) {
+	dir := d
+	if strings.HasPrefix(path, "/") {
+		dir = d.root()
+	}
+	for _, name := range strings.Split(path, "/") {
+		switch name {
+		case "", ".":
+			{
+				continue
+			}
+		case "..":
+			{
+				if dir.parent != nil {
+					dir = dir.parent
+				}
+			}
+		default:
+			{
+				next, ok := dir.subdirectories[name]
+				if !ok {
+					if _, isFile := dir.files[name]; isFile {
+						return nil, fmt.Errorf("%s: not a directory", path)
+					}
+					return nil, fmt.Errorf("%s: no such directory", path)
+				}
+				dir = next
+			}
+		}
+	}
+	return dir, nil
+}
+
+func (d *Directory) sortedSubdirectories() []*Directory {
+	dirs := make([]*Directory, 0, len(d.subdirectories))
+	for _, dir := range d.subdirectories {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].name < dirs[j].name
+	})
+	return dirs
+}
+
+func (d *Directory) sortedFiles() []*File {
+	files := make([]*File, 0, len(d.files))
+	for _, file := range d.files {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].name < files[j].name
+	})
+	return files
+}
+
+// jsonEntry is how files and directories are written by MarshalJSON
+type jsonEntry struct {
+	Name     string      `json:"name"`
+	Type     string      `json:"type"`
+	Size     int         `json:"size"`
+	Children []jsonEntry `json:"children,omitempty"`
+}
+
+// MarshalJSON writes the directory and everything in it as nested entries
+// with a name, type ("dir" or "file"), size and, for directories, children
+// sorted by name
+func (d *Directory) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.jsonEntry())
+}
+
+func (d *Directory) jsonEntry() jsonEntry {
+	entry := jsonEntry{Name: d.name, Type: "dir", Size: d.Size(), Children: make([]jsonEntry, 0)}
+	for _, dir := range d.sortedSubdirectories() {
+		entry.Children = append(entry.Children, dir.jsonEntry())
+	}
+	for _, file := range d.sortedFiles() {
+		entry.Children = append(entry.Children, jsonEntry{Name: file.name, Type: "file", Size: file.size})
+	}
+	return entry
+}
+
+// Size returns the total size of the files in the directory and all of its
+// subdirectories
+func (d *Directory) Size() (sum int) {
+	if d.size != -1 {
+		return d.size
+	}
+	for _, dir := range d.subdirectories {
+		sum += dir.Size()
+	}
+	for _, file := range d.files {
+		sum += file.size
+	}
+
+	d.size = sum
+	return
+}
+
+// Crawl the entire file system from the root and return a list of
+// directories whose size (including subdirectories) is smaller than the
+// provided maximum size
+func getDirectoriesSmallerThan(root *Directory, max int) [](*Directory) {
+	dirs := make([]*Directory, 0)
+	rootSize := root.Size()
+	if rootSize <= max {
+		dirs = append(dirs, root)
+	}
+
+	for _, child := range root.subdirectories {
+		dirs = append(dirs, getDirectoriesSmallerThan(child, max)...)
+	}
+
+	return dirs
+}
+
+func sumDirectorySizes(dirs []*Directory) (sum int) {
+	for _, d := range dirs {
+		sum += d.Size()
+	}
+	return
+}
+
+// FindDirectoryToDelete returns the smallest directory that frees up enough
+// space on the disk for the update
+func FindDirectoryToDelete(root *Directory, disk Disk) (*Directory, error) {
+	spaceToBeFreed := disk.ToFree(root)
+
+	allDirs := getDirectoriesSmallerThan(root, math.MaxInt)
+
+	sort.Slice(allDirs, func(i, j int) bool {
+		return allDirs[i].Size() < allDirs[j].Size()
+	})
+
+	dirIndex := sort.Search(len(allDirs), func(i int) bool {
+		return allDirs[i].Size() >= spaceToBeFreed
+	})
+	if dirIndex == len(allDirs) {
+		return nil, fmt.Errorf("no directory is large enough to free up %d", spaceToBeFreed)
+	}
+
+	return allDirs[dirIndex], nil
+}
+
+// Free returns the space left on the disk
+func (disk Disk) Free(root *Directory) int {
+	return disk.Capacity - root.Size()
+}
+
+// ToFree returns how much more space has to be freed for the update, which
+// may be negative if there is already enough
+func (disk Disk) ToFree(root *Directory) int {
+	return disk.Needed - disk.Free(root)
+}
@@ -0,0 +1,169 @@
+package day7
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+func readTestFileSystem(t *testing.T) *Directory {
+	t.Helper()
+	f, err := os.Open("../test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	root, err := ReadFileSystem(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestShell(t *testing.T) {
+	tests := []struct {
+		commands string
+		want     string
+	}{
+		{commands: "ls a", want: "dir e\n29116 f\n2557 g\n62596 h.lst\n"},
+		{commands: "cd a/e\ncd ../..\ncd d\nls", want: "5626152 d.ext\n8033020 d.log\n4060174 j\n7214296 k\n"},
+		{commands: "du", want: "584\t/a/e\n94853\t/a\n24933642\t/d\n48381165\t/\n"},
+		{commands: "du -h d", want: "24M\t/d\n"},
+		{commands: "find -type d -size -100001", want: "/a\n/a/e\n"},
+		{commands: "find a -type f -size +60k", want: "/a/h.lst\n"},
+		{commands: "find / -size 584", want: "/a/e\n/a/e/i\n"},
+	}
+
+	root := readTestFileSystem(t)
+	for _, test := range tests {
+		var out strings.Builder
+		sh := NewShell(root, DefaultDisk, &out)
+		for _, line := range strings.Split(test.commands, "\n") {
+			if err := sh.Run(strings.Fields(line)); err != nil {
+				t.Fatalf("%s: %s", line, err)
+			}
+		}
+		if got := out.String(); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.commands, got, test.want)
+		}
+	}
+}
+
+func TestShellErrors(t *testing.T) {
+	root := readTestFileSystem(t)
+	sh := NewShell(root, DefaultDisk, &strings.Builder{})
+	for _, line := range []string{"cd nope", "cd b.txt", "ls a d", "find -size x", "find -type q", "frobnicate"} {
+		if err := sh.Run(strings.Fields(line)); err == nil {
+			t.Errorf("%q: expected an error", line)
+		}
+	}
+}
+
+func TestDiskIsConfigurable(t *testing.T) {
+	root := readTestFileSystem(t)
+	dir, err := FindDirectoryToDelete(root, Disk{Capacity: 50000000, Needed: 10000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir.Path() != "/d" {
+		t.Errorf("got %s, want /d", dir.Path())
+	}
+	if _, err := FindDirectoryToDelete(root, Disk{Capacity: 1, Needed: 2}); err == nil {
+		t.Error("expected an error when nothing can be deleted to make room")
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	root := readTestFileSystem(t)
+	dat, err := json.Marshal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry jsonEntry
+	if err := json.Unmarshal(dat, &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Size != 48381165 || len(entry.Children) != 4 {
+		t.Fatalf("got %+v", entry)
+	}
+	// Directories come first, then files, each sorted by name
+	names := make([]string, 0)
+	for _, child := range entry.Children {
+		names = append(names, child.Name+":"+child.Type)
+	}
+	if got := strings.Join(names, " "); got != "a:dir d:dir b.txt:file c.dat:file" {
+		t.Errorf("got children %s", got)
+	}
+}
+
+func TestTranscripts(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		size       int
+	}{
+		{
+			name:       "cd / mid-session and re-listing",
+			transcript: "$ cd /\n$ ls\ndir a\n10 x\n$ cd a\n$ ls\n5 y\n$ cd /\n$ ls\n10 x\ndir a\n$ cd a\n$ ls\n5 y\n",
+			size:       15,
+		},
+		{
+			name:       "absolute and multi-part paths",
+			transcript: "$ ls\ndir a\n$ cd /a\n$ ls\ndir b\n$ cd b\n$ ls\n7 z\n$ cd ../../a/b/..\n$ ls\ndir b\n",
+			size:       7,
+		},
+	}
+
+	for _, test := range tests {
+		root, err := readFileSystem(test.transcript)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+		if root.Size() != test.size {
+			t.Errorf("%s: got size %d, want %d", test.name, root.Size(), test.size)
+		}
+	}
+}
+
+func TestInvalidTranscripts(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		line       int
+		col        int
+	}{
+		{name: "cd .. at root", transcript: "$ cd /\n$ cd ..\n", line: 2, col: 6},
+		{name: "cd before listing", transcript: "$ cd /\n$ cd a\n", line: 2, col: 6},
+		{name: "cd to an unlisted directory", transcript: "$ ls\n1 x\n$ cd a\n", line: 3, col: 6},
+		{name: "cd into a file", transcript: "$ ls\n1 x\n$ cd x\n", line: 3, col: 6},
+		{name: "listed twice in one ls", transcript: "$ ls\n1 x\n2 x\n", line: 3, col: 1},
+		{name: "size changed", transcript: "$ ls\n1 x\n$ ls\n2 x\n", line: 4, col: 1},
+		{name: "entry appeared", transcript: "$ ls\n1 x\n$ ls\n1 x\n2 y\n", line: 5, col: 1},
+		{name: "entry disappeared", transcript: "$ ls\n1 x\n2 y\n$ ls\n1 x\n", line: 4, col: 1},
+		{name: "file became a directory", transcript: "$ ls\n1 x\n$ ls\ndir x\n", line: 4, col: 1},
+		{name: "directory never listed", transcript: "$ ls\ndir a\n1 x\n", line: 2, col: 0},
+		{name: "negative size", transcript: "$ ls\n-1 x\n", line: 2, col: 1},
+		{name: "name with a slash", transcript: "$ ls\n1 a/b\n", line: 2, col: 3},
+	}
+
+	for _, test := range tests {
+		_, err := readFileSystem(test.transcript)
+		if err == nil {
+			t.Errorf("%s: expected an error", test.name)
+			continue
+		}
+		var parseErr *parse.Error
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s: got %q, want a parse error", test.name, err)
+			continue
+		}
+		if parseErr.Line != test.line || parseErr.Col != test.col {
+			t.Errorf("%s: got %q, want it at line %d, col %d", test.name, err, test.line, test.col)
+		}
+	}
+}
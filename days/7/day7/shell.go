@@ -0,0 +1,349 @@
+package day7
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const SHELL_HELP = `commands:
+  cd <dir>                    change directory (absolute, relative, .. or /)
+  ls [dir]                    list a directory the way the transcript does
+  du [-h] [dir]               show the size of each directory below dir
+  find [dir] [-type d|f] [-size [+|-]N[k|M|G]]
+                              list files and directories by type and size
+  tree [dir]                  draw the directories and files below dir
+  df [-h]                     show how the disk is used, and what to delete
+  exit                        stop
+`
+
+// Shell runs commands against a filesystem read from a transcript
+type Shell struct {
+	cwd  *Directory
+	disk Disk
+	out  io.Writer
+}
+
+// NewShell returns a shell at the root of the filesystem, writing its output
+// to out
+func NewShell(root *Directory, disk Disk, out io.Writer) *Shell {
+	return &Shell{cwd: root.root(), disk: disk, out: out}
+}
+
+// Interactive reads commands from in until it runs out or reads exit,
+// printing a prompt before each one. Failed commands print their error and
+// carry on.
+func (sh *Shell) Interactive(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	fmt.Fprint(sh.out, SHELL_HELP)
+	for {
+		fmt.Fprintf(sh.out, "%s $ ", sh.cwd.Path())
+		if !scanner.Scan() {
+			fmt.Fprintln(sh.out)
+			return scanner.Err()
+		}
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "exit" || args[0] == "quit" {
+			return nil
+		}
+		if err := sh.Run(args); err != nil {
+			fmt.Fprintf(sh.out, "%s: %s\n", args[0], err)
+		}
+	}
+}
+
+// Run runs a single command, given as its name followed by its arguments
+func (sh *Shell) Run(args []string) error {
+	switch args[0] {
+	case "cd":
+		{
+			if len(args) != 2 {
+				return errors.New("usage: cd <dir>")
+			}
+			dir, err := sh.cwd.Lookup(args[1])
+			if err != nil {
+				return err
+			}
+			sh.cwd = dir
+			return nil
+		}
+	case "ls":
+		{
+			dir, err := sh.target(args[1:])
+			if err != nil {
+				return err
+			}
+			for _, sub := range dir.sortedSubdirectories() {
+				fmt.Fprintf(sh.out, "dir %s\n", sub.name)
+			}
+			for _, file := range dir.sortedFiles() {
+				fmt.Fprintf(sh.out, "%d %s\n", file.size, file.name)
+			}
+			return nil
+		}
+	case "du":
+		{
+			human, rest := hasFlag(args[1:], "-h")
+			dir, err := sh.target(rest)
+			if err != nil {
+				return err
+			}
+			sh.du(dir, human)
+			return nil
+		}
+	case "find":
+		{
+			return sh.find(args[1:])
+		}
+	case "tree":
+		{
+			dir, err := sh.target(args[1:])
+			if err != nil {
+				return err
+			}
+			printDirectoryTree(sh.out, dir, "")
+			return nil
+		}
+	case "df":
+		{
+			human, rest := hasFlag(args[1:], "-h")
+			if len(rest) != 0 {
+				return errors.New("usage: df [-h]")
+			}
+			sh.df(human)
+			return nil
+		}
+	case "help":
+		{
+			fmt.Fprint(sh.out, SHELL_HELP)
+			return nil
+		}
+	default:
+		{
+			return errors.New("unknown command, try help")
+		}
+	}
+}
+
+// target returns the directory named by the only argument, or the current
+// directory if there are none
+func (sh *Shell) target(args []string) (*Directory, error) {
+	switch len(args) {
+	case 0:
+		{
+			return sh.cwd, nil
+		}
+	case 1:
+		{
+			return sh.cwd.Lookup(args[0])
+		}
+	default:
+		{
+			return nil, errors.New("too many arguments")
+		}
+	}
+}
+
+// du lists every directory below dir, deepest first, as du does
+func (sh *Shell) du(dir *Directory, human bool) {
+	for _, sub := range dir.sortedSubdirectories() {
+		sh.du(sub, human)
+	}
+	fmt.Fprintf(sh.out, "%s\t%s\n", formatSize(dir.Size(), human), dir.Path())
+}
+
+func (sh *Shell) df(human bool) {
+	root := sh.cwd.root()
+	fmt.Fprintf(sh.out, "capacity\t%s\n", formatSize(sh.disk.Capacity, human))
+	fmt.Fprintf(sh.out, "used\t\t%s\n", formatSize(root.Size(), human))
+	fmt.Fprintf(sh.out, "free\t\t%s\n", formatSize(sh.disk.Free(root), human))
+	fmt.Fprintf(sh.out, "needed\t\t%s\n", formatSize(sh.disk.Needed, human))
+
+	if sh.disk.ToFree(root) <= 0 {
+		fmt.Fprintln(sh.out, "there is already enough free space")
+		return
+	}
+	dir, err := FindDirectoryToDelete(root, sh.disk)
+	if err != nil {
+		fmt.Fprintln(sh.out, err)
+		return
+	}
+	fmt.Fprintf(sh.out, "delete %s to free up %s\n", dir.Path(), formatSize(dir.Size(), human))
+}
+
+// sizeFilter matches sizes the way find -size does: +N matches sizes larger
+// than N, -N smaller than N, and N exactly N
+type sizeFilter struct {
+	cmp  int
+	size int
+}
+
+func (f sizeFilter) matches(size int) bool {
+	switch {
+	case f.cmp > 0:
+		{
+			return size > f.size
+		}
+	case f.cmp < 0:
+		{
+			return size < f.size
+		}
+	default:
+		{
+			return size == f.size
+		}
+	}
+}
+
+func (sh *Shell) find(args []string) error {
+	start := sh.cwd
+	kind := ""
+	var filter *sizeFilter
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-type":
+			{
+				if i+1 == len(args) || (args[i+1] != "d" && args[i+1] != "f") {
+					return errors.New("-type takes d or f")
+				}
+				kind = args[i+1]
+				i++
+			}
+		case "-size":
+			{
+				if i+1 == len(args) {
+					return errors.New("-size takes a size like +100k")
+				}
+				f, err := parseSizeFilter(args[i+1])
+				if err != nil {
+					return err
+				}
+				filter = &f
+				i++
+			}
+		default:
+			{
+				if i != 0 {
+					return fmt.Errorf("unexpected argument %q", args[i])
+				}
+				dir, err := sh.cwd.Lookup(args[i])
+				if err != nil {
+					return err
+				}
+				start = dir
+			}
+		}
+	}
+
+	var walk func(dir *Directory)
+	walk = func(dir *Directory) {
+		if kind != "f" && (filter == nil || filter.matches(dir.Size())) {
+			fmt.Fprintln(sh.out, dir.Path())
+		}
+		if kind != "d" {
+			for _, file := range dir.sortedFiles() {
+				if filter == nil || filter.matches(file.size) {
+					fmt.Fprintln(sh.out, dir.child(file.name))
+				}
+			}
+		}
+		for _, sub := range dir.sortedSubdirectories() {
+			walk(sub)
+		}
+	}
+	walk(start)
+	return nil
+}
+
+func parseSizeFilter(s string) (sizeFilter, error) {
+	f := sizeFilter{}
+	text := s
+	switch {
+	case strings.HasPrefix(text, "+"):
+		{
+			f.cmp = 1
+			text = text[1:]
+		}
+	case strings.HasPrefix(text, "-"):
+		{
+			f.cmp = -1
+			text = text[1:]
+		}
+	}
+
+	unit := 1
+	if len(text) > 0 {
+		switch text[len(text)-1] {
+		case 'k', 'K':
+			{
+				unit = 1 << 10
+			}
+		case 'M':
+			{
+				unit = 1 << 20
+			}
+		case 'G':
+			{
+				unit = 1 << 30
+			}
+		}
+		if unit != 1 {
+			text = text[:len(text)-1]
+		}
+	}
+
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 {
+		return f, fmt.Errorf("invalid size %q", s)
+	}
+	f.size = n * unit
+	return f, nil
+}
+
+func hasFlag(args []string, flag string) (bool, []string) {
+	found := false
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == flag {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return found, rest
+}
+
+// formatSize writes sizes in bytes, or with -h in powers of 1024 with one
+// decimal place below 10, as du -h does
+func formatSize(size int, human bool) string {
+	if !human {
+		return fmt.Sprint(size)
+	}
+	units := []string{"", "K", "M", "G", "T"}
+	value := float64(size)
+	unit := 0
+	for (value >= 1024 || value <= -1024) && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	switch {
+	case unit == 0:
+		{
+			return fmt.Sprint(size)
+		}
+	case value < 10 && value > -10:
+		{
+			return fmt.Sprintf("%.1f%s", value, units[unit])
+		}
+	default:
+		{
+			return fmt.Sprintf("%.0f%s", value, units[unit])
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package day8
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+)
+
+type TreeGrid struct {
+	*grid.Dense[int]
+}
+
+// Part1 returns the number of trees visible from outside the grid
+func Part1(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	grid, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(countVisibleTrees(grid)), nil
+}
+
+// Part2 returns the highest scenic score of any tree
+func Part2(r io.Reader) (string, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return "", err
+	}
+	grid, err := parseInput(strings.TrimSpace(input))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(findBestTree(grid)), nil
+}
+
+// ReadTrees reads a map of tree heights
+func ReadTrees(r io.Reader) (TreeGrid, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return TreeGrid{}, err
+	}
+	return parseInput(strings.TrimSpace(input))
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) (TreeGrid, error) {
+	trees, err := grid.ParseStrict(input, func(p grid.Vec2, c byte) (int, error) {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid tree height %q", c)
+		}
+		return int(c - '0'), nil
+	})
+	return TreeGrid{trees}, err
+}
+
+// Views describes what can be seen from, and of, every tree in the grid
+type Views struct {
+	// Whether each tree can be seen from outside the grid
+	Visible *grid.Dense[bool]
+	// The scenic score of each tree: the product of how far it can see in
+	// each direction
+	Scores *grid.Dense[int]
+}
+
+// Survey works out the views for every tree at once. Each row and column is
+// walked once in each direction, keeping a stack of the trees that could still
+// block the view, so the whole grid takes time proportional to its area.
+func Survey(g TreeGrid) Views {
+	width, height := g.Width(), g.Height()
+	views := Views{
+		Visible: grid.NewDense[bool](width, height),
+		Scores:  grid.NewDense[int](width, height),
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			views.Scores.Set(grid.Vec2{X: x, Y: y}, 1)
+		}
+	}
+
+	stack := make([]int, 0, maxInt(width, height))
+	for y := 0; y < height; y++ {
+		// Looking left, then looking right
+		stack = g.lookBack(&views, grid.Vec2{X: 0, Y: y}, grid.Vec2{X: 1, Y: 0}, width, stack)
+		stack = g.lookBack(&views, grid.Vec2{X: width - 1, Y: y}, grid.Vec2{X: -1, Y: 0}, width, stack)
+	}
+	for x := 0; x < width; x++ {
+		// Looking up, then looking down
+		stack = g.lookBack(&views, grid.Vec2{X: x, Y: 0}, grid.Vec2{X: 0, Y: 1}, height, stack)
+		stack = g.lookBack(&views, grid.Vec2{X: x, Y: height - 1}, grid.Vec2{X: 0, Y: -1}, height, stack)
+	}
+
+	return views
+}
+
+// lookBack walks the n trees from start in steps of step, looking from each
+// tree back the way it came. The stack holds the trees passed so far that
+// aren't hidden behind a later tree at least as tall, so their heights
+// decrease from the bottom up: the first tree left on it after popping the
+// shorter ones is the one that blocks the view.
+func (g *TreeGrid) lookBack(views *Views, start grid.Vec2, step grid.Vec2, n int, stack []int) []int {
+	stack = stack[:0]
+	for i := 0; i < n; i++ {
+		p := start.Add(step.Times(i))
+		height := g.At(p)
+		for len(stack) > 0 && g.At(start.Add(step.Times(stack[len(stack)-1]))) < height {
+			stack = stack[:len(stack)-1]
+		}
+
+		distance := i
+		if len(stack) == 0 {
+			views.Visible.Set(p, true)
+		} else {
+			distance = i - stack[len(stack)-1]
+		}
+		views.Scores.Set(p, views.Scores.At(p)*distance)
+
+		stack = append(stack, i)
+	}
+	return stack
+}
+
+func countVisibleTrees(g TreeGrid) (sum int) {
+	visible := Survey(g).Visible
+	for y := 0; y < visible.Height(); y++ {
+		for x := 0; x < visible.Width(); x++ {
+			if visible.At(grid.Vec2{X: x, Y: y}) {
+				sum++
+			}
+		}
+	}
+
+	return
+}
+
+func findBestTree(g TreeGrid) (maxScore int) {
+	scores := Survey(g).Scores
+	for y := 0; y < scores.Height(); y++ {
+		for x := 0; x < scores.Width(); x++ {
+			maxScore = maxInt(maxScore, scores.At(grid.Vec2{X: x, Y: y}))
+		}
+	}
+
+	return
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
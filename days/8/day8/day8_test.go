@@ -0,0 +1,55 @@
+package day8
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/FaideWW/aoc-2022/grid"
+)
+
+// bruteForceView walks from p towards the edge, returning how many trees can
+// be seen and whether the edge was reached without being blocked
+func bruteForceView(g TreeGrid, p0 grid.Vec2, direction grid.Vec2) (distance int, visible bool) {
+	height := g.At(p0)
+	for p := p0.Add(direction); g.InBounds(p); p = p.Add(direction) {
+		distance++
+		if g.At(p) >= height {
+			return distance, false
+		}
+	}
+	return distance, true
+}
+
+func TestSurveyMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		width, height := rng.Intn(20)+1, rng.Intn(20)+1
+		// Few distinct heights make ties, which are the interesting case
+		levels := rng.Intn(10) + 1
+		g := TreeGrid{grid.NewDense[int](width, height)}
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				g.Set(grid.Vec2{X: x, Y: y}, rng.Intn(levels))
+			}
+		}
+
+		views := Survey(g)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				p := grid.Vec2{X: x, Y: y}
+				score, visible := 1, false
+				for _, direction := range grid.Directions4 {
+					distance, fromOutside := bruteForceView(g, p, direction)
+					score *= distance
+					visible = visible || fromOutside
+				}
+				if got := views.Scores.At(p); got != score {
+					t.Fatalf("%dx%d grid: score at %v is %d, want %d", width, height, p, got, score)
+				}
+				if got := views.Visible.At(p); got != visible {
+					t.Fatalf("%dx%d grid: visibility at %v is %t, want %t", width, height, p, got, visible)
+				}
+			}
+		}
+	}
+}
@@ -1,221 +1,97 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"image"
+	"image/png"
 	"math"
 	"os"
-	"strings"
-)
-
-type TreeGrid [][]int
+	"path/filepath"
 
-func check(e error) {
-	if e != nil {
-		panic(e)
-	}
-}
+	"github.com/FaideWW/aoc-2022/days/8/day8"
+	"github.com/FaideWW/aoc-2022/grid"
+	"github.com/FaideWW/aoc-2022/solver"
+)
 
 func main() {
-	input := strings.TrimSpace(readInputFile(os.Args[1]))
-	grid := parseInput(input)
-	visibleTrees := countVisibleTrees(grid)
-	fmt.Printf("there are %d visible trees\n", visibleTrees)
-
-	topScore := findBestTree(grid)
-	fmt.Printf("max scenic score: %d\n", topScore)
-}
-
-func readInputFile(filename string) string {
-	dat, err := os.ReadFile(filename)
-	check(err)
-	return string(dat)
-}
-
-func parseInput(input string) TreeGrid {
-	lines := strings.Split(input, "\n")
-	grid := make([][]int, len(lines))
-
-	for i, line := range lines {
-		row := make([]int, len(line))
-		for n, rune := range line {
-			row[n] = int(rune - '0')
-		}
-
-		grid[i] = row
-	}
-
-	return grid
-}
-
-func countVisibleTrees(grid TreeGrid) (sum int) {
-	gridHeight := len(grid)
-	gridWidth := len(grid[0])
-
-	// Outer trees are always visible
-	sum += gridWidth*2 + (gridHeight-2)*2
-
-	for y := 1; y < gridHeight-1; y++ {
-		for x := 1; x < gridWidth-1; x++ {
-			if grid.isTreeVisible(x, y) {
-				sum++
-			}
-		}
+	visible := flag.String("visible", "", "write a map of the visible trees to this .png or .pgm file")
+	scores := flag.String("scores", "", "write a heatmap of the scenic scores to this .png or .pgm file")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [--visible file] [--scores file] <input file>\n", os.Args[0])
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	return
-}
-
-func (g *TreeGrid) isTreeVisible(x int, y int) bool {
-	return (g.isTreeVisibleFromLeft(x, y) ||
-		g.isTreeVisibleFromRight(x, y) ||
-		g.isTreeVisibleFromTop(x, y) ||
-		g.isTreeVisibleFromBottom(x, y))
-}
-
-func (g *TreeGrid) isTreeVisibleFromLeft(x0 int, y0 int) bool {
-	if x0 == 0 {
-		return true
-	}
-	height := (*g)[y0][x0]
-	for x := x0 - 1; x >= 0; x-- {
-		if (*g)[y0][x] >= height {
-			return false
-		}
+	if *visible == "" && *scores == "" {
+		solver.Main(solver.Day{Part1: day8.Part1, Part2: day8.Part2})
+		return
 	}
 
-	return true
-}
-
-func (g *TreeGrid) isTreeVisibleFromRight(x0 int, y0 int) bool {
-	rowSize := len((*g)[y0])
-	if x0 == rowSize-1 {
-		return true
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
 	}
-	height := (*g)[y0][x0]
-	for x := x0 + 1; x < rowSize; x++ {
-		if (*g)[y0][x] >= height {
-			return false
-		}
+	if err := run(flag.Arg(0), *visible, *scores); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-
-	return true
 }
 
-func (g *TreeGrid) isTreeVisibleFromTop(x0 int, y0 int) bool {
-	if y0 == 0 {
-		return true
+func run(path string, visiblePath string, scoresPath string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
 	}
-	height := (*g)[y0][x0]
-	for y := y0 - 1; y >= 0; y-- {
-		if (*g)[y][x0] >= height {
-			return false
-		}
+	defer f.Close()
+	trees, err := day8.ReadTrees(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
 	}
+	views := day8.Survey(trees)
 
-	return true
-}
-
-func (g *TreeGrid) isTreeVisibleFromBottom(x0 int, y0 int) bool {
-	colSize := len(*g)
-	if y0 == colSize-1 {
-		return true
-	}
-	height := (*g)[y0][x0]
-	for y := y0 + 1; y < colSize; y++ {
-		if (*g)[y][x0] >= height {
-			return false
-		}
-	}
-
-	return true
-}
-
-func findBestTree(grid TreeGrid) (maxScore int) {
-	gridHeight := len(grid)
-	gridWidth := len(grid[0])
-
-	maxScore = math.MinInt
-	for y := 1; y < gridHeight-1; y++ {
-		for x := 1; x < gridWidth-1; x++ {
-			scenicScore := grid.getScenicScore(x, y)
-			if scenicScore > maxScore {
-				maxScore = scenicScore
+	if visiblePath != "" {
+		img := grid.Heatmap(views.Visible, func(v bool) float64 {
+			if v {
+				return 1
 			}
+			return 0
+		})
+		if err := writeImage(visiblePath, img); err != nil {
+			return err
 		}
 	}
-
-	return
-}
-
-func (g *TreeGrid) getScenicScore(x int, y int) int {
-	return (g.getVisibilityLeft(x, y) *
-		g.getVisibilityRight(x, y) *
-		g.getVisibilityTop(x, y) *
-		g.getVisibilityBottom(x, y))
-}
-
-func (g *TreeGrid) getVisibilityLeft(x0 int, y0 int) (visibility int) {
-	if x0 == 0 {
-		return 0
-	}
-
-	height := (*g)[y0][x0]
-	for x := x0 - 1; x >= 0; x-- {
-		visibility++
-		if (*g)[y0][x] >= height {
-			break
+	if scoresPath != "" {
+		// Scores span several orders of magnitude, so shade them by their
+		// logarithm to keep more than the very best trees visible
+		img := grid.Heatmap(views.Scores, func(score int) float64 {
+			return math.Log1p(float64(score))
+		})
+		if err := writeImage(scoresPath, img); err != nil {
+			return err
 		}
 	}
-
-	return
+	return nil
 }
 
-func (g *TreeGrid) getVisibilityRight(x0 int, y0 int) (visibility int) {
-	rowSize := len((*g)[y0])
-	if x0 == rowSize-1 {
-		return 0
+// writeImage picks the format from the file's extension
+func writeImage(path string, img *image.Gray) error {
+	ext := filepath.Ext(path)
+	if ext != ".png" && ext != ".pgm" {
+		return fmt.Errorf("%s: expected a .png or .pgm file", path)
 	}
 
-	height := (*g)[y0][x0]
-	for x := x0 + 1; x < rowSize; x++ {
-		visibility++
-		if (*g)[y0][x] >= height {
-			break
-		}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
 	}
-
-	return
-}
-
-func (g *TreeGrid) getVisibilityTop(x0 int, y0 int) (visibility int) {
-	if y0 == 0 {
-		return 0
+	if ext == ".png" {
+		err = png.Encode(f, img)
+	} else {
+		err = grid.WritePGM(f, img)
 	}
-
-	height := (*g)[y0][x0]
-	for y := y0 - 1; y >= 0; y-- {
-		visibility++
-		if (*g)[y][x0] >= height {
-			break
-		}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
 	}
-
-	return
-}
-
-func (g *TreeGrid) getVisibilityBottom(x0 int, y0 int) (visibility int) {
-	colSize := len(*g)
-	if y0 == colSize-1 {
-		return 0
-	}
-
-	height := (*g)[y0][x0]
-	for y := y0 + 1; y < colSize; y++ {
-		visibility++
-		if (*g)[y][x0] >= height {
-			break
-		}
-	}
-
-	return
+	return err
 }
@@ -0,0 +1,213 @@
+package day9
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+const SHORT_ROPE_LENGTH = 2
+const LONG_ROPE_LENGTH = 10
+
+// The directions a move can combine, one per axis. Ropes on a flat map only
+// use the first four; F and B move along the Z axis.
+var DIRECTIONS = map[byte]grid.Vec3{
+	'R': {X: 1},
+	'L': {X: -1},
+	'U': {Y: -1},
+	'D': {Y: 1},
+	'F': {Z: 1},
+	'B': {Z: -1},
+}
+
+// Move steps the head of the rope Count times in the same direction. The
+// direction is a single step on up to three axes, so "UR 2" moves the head
+// diagonally twice.
+type Move struct {
+	Direction grid.Vec3
+	Count     int
+	// The direction as written in the input
+	name string
+}
+
+// Rope is a line of knots, each following the one before it, starting with
+// the head
+type Rope struct {
+	Knots []grid.Vec3
+	// The positions each knot has visited, including where it started
+	trails []map[grid.Vec3]bool
+	// The number of steps taken by the head so far
+	steps int
+}
+
+// Part1 returns the number of tiles visited by the tail of a 2-knot rope
+func Part1(r io.Reader) (string, error) {
+	short, _, err := tailVisits(r)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(short), nil
+}
+
+// Part2 returns the number of tiles visited by the tail of a 10-knot rope
+func Part2(r io.Reader) (string, error) {
+	_, long, err := tailVisits(r)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(long), nil
+}
+
+// tailVisits returns the number of tiles visited by the tails of both ropes.
+// Every knot moves just like the tail of a shorter rope, so a single run of
+// the long rope answers for both.
+func tailVisits(r io.Reader) (short int, long int, err error) {
+	moves, err := ReadMoves(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	rope := NewRope(LONG_ROPE_LENGTH)
+	rope.Run(moves, nil)
+	return rope.Visited(SHORT_ROPE_LENGTH - 1), rope.Visited(LONG_ROPE_LENGTH - 1), nil
+}
+
+// ReadMoves reads the moves of the head, one per line
+func ReadMoves(r io.Reader) ([]Move, error) {
+	input, err := readInput(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseInput(strings.TrimSpace(input))
+}
+
+func readInput(r io.Reader) (string, error) {
+	dat, err := io.ReadAll(r)
+	return string(dat), err
+}
+
+func parseInput(input string) ([]Move, error) {
+	lines := parse.Lines(input)
+	moves := make([]Move, 0, len(lines))
+	for _, line := range lines {
+		parts, err := line.SplitN(" ", 2, "a move like \"R 4\"")
+		if err != nil {
+			return nil, err
+		}
+		direction, err := parseDirection(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		amt, err := parts[1].Int()
+		if err != nil {
+			return nil, err
+		}
+		if amt < 0 {
+			return nil, parts[1].Errorf("can't move a negative distance")
+		}
+
+		moves = append(moves, Move{Direction: direction, Count: amt, name: parts[0].Text})
+	}
+	return moves, nil
+}
+
+// parseDirection adds up the directions named by each letter, allowing at
+// most one per axis
+func parseDirection(input parse.Field) (grid.Vec3, error) {
+	var direction grid.Vec3
+	for i := 0; i < len(input.Text); i++ {
+		step, ok := DIRECTIONS[input.Text[i]]
+		if !ok {
+			return grid.Vec3{}, input.Errorf("invalid direction %q", input.Text)
+		}
+		sum := direction.Add(step)
+		if sum.Chebyshev() > 1 || sum.Manhattan() != direction.Manhattan()+1 {
+			return grid.Vec3{}, input.Errorf("direction %q moves along the same axis twice", input.Text)
+		}
+		direction = sum
+	}
+	return direction, nil
+}
+
+func (m Move) String() string {
+	return fmt.Sprintf("%s %d", m.name, m.Count)
+}
+
+// NewRope returns a rope of length knots, all starting at the origin
+func NewRope(length int) *Rope {
+	if length < 1 {
+		panic(fmt.Sprintf("day9: a rope needs at least one knot, not %d", length))
+	}
+	r := &Rope{
+		Knots:  make([]grid.Vec3, length),
+		trails: make([]map[grid.Vec3]bool, length),
+	}
+	for i := range r.trails {
+		r.trails[i] = map[grid.Vec3]bool{{}: true}
+	}
+	return r
+}
+
+// Step moves the head one step in the given direction and lets the rest of
+// the rope follow
+func (r *Rope) Step(direction grid.Vec3) {
+	r.Knots[0] = r.Knots[0].Add(direction)
+	r.trails[0][r.Knots[0]] = true
+	for i := 1; i < len(r.Knots); i++ {
+		shouldMove, newPos := shouldTailMove(r.Knots[i-1], r.Knots[i])
+		if !shouldMove {
+			// Nothing further down the rope can move either
+			break
+		}
+		r.Knots[i] = newPos
+		r.trails[i][newPos] = true
+	}
+	r.steps++
+}
+
+// Run carries out the moves, calling after (if it isn't nil) after every step
+// with the index of the move and how many of its steps have been taken
+func (r *Rope) Run(moves []Move, after func(move int, step int)) {
+	for i, move := range moves {
+		for step := 1; step <= move.Count; step++ {
+			r.Step(move.Direction)
+			if after != nil {
+				after(i, step)
+			}
+		}
+	}
+}
+
+// Steps returns the number of steps the head has taken
+func (r *Rope) Steps() int {
+	return r.steps
+}
+
+// Visited returns the number of positions the knot has been at. A knot moves
+// the same way as the tail of a rope that ends with it, so the knots of one
+// long rope answer the question for every shorter rope too.
+func (r *Rope) Visited(knot int) int {
+	return len(r.trails[knot])
+}
+
+// Trail returns the positions the knot has been at
+func (r *Rope) Trail(knot int) []grid.Vec3 {
+	trail := make([]grid.Vec3, 0, len(r.trails[knot]))
+	for p := range r.trails[knot] {
+		trail = append(trail, p)
+	}
+	return trail
+}
+
+func shouldTailMove(head grid.Vec3, tail grid.Vec3) (bool, grid.Vec3) {
+	// The tail only moves once it is no longer touching the head (including
+	// diagonally), and then takes a single step towards it on each axis
+	direction := head.Sub(tail)
+	if direction.Chebyshev() <= 1 {
+		return false, tail
+	}
+
+	return true, tail.Add(direction.Sign())
+}
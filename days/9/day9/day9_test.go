@@ -0,0 +1,183 @@
+package day9
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/gif"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/FaideWW/aoc-2022/grid"
+)
+
+func readTestMoves(t *testing.T) []Move {
+	t.Helper()
+	f, err := os.Open("../test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	moves, err := ReadMoves(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return moves
+}
+
+func TestKnotsMatchShorterRopes(t *testing.T) {
+	moves := readTestMoves(t)
+	long := NewRope(LONG_ROPE_LENGTH)
+	long.Run(moves, nil)
+	for length := 1; length <= LONG_ROPE_LENGTH; length++ {
+		short := NewRope(length)
+		short.Run(moves, nil)
+		if got, want := long.Visited(length-1), short.Visited(length-1); got != want {
+			t.Errorf("knot %d visited %d positions, but the tail of a %d-knot rope visits %d", length-1, got, length, want)
+		}
+	}
+}
+
+func TestDirections(t *testing.T) {
+	moves, err := parseInput("UR 2\nDLB 1\nF 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []grid.Vec3{{X: 1, Y: -1}, {X: -1, Y: 1, Z: -1}, {Z: 1}}
+	for i, move := range moves {
+		if move.Direction != want[i] {
+			t.Errorf("move %d: got %v, want %v", i, move.Direction, want[i])
+		}
+	}
+
+	for _, input := range []string{"UD 1", "RR 1", "X 1", "UX 1"} {
+		if _, err := parseInput(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestDiagonalAnd3DRopes(t *testing.T) {
+	moves, err := parseInput("UR 3\nF 4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rope := NewRope(3)
+	rope.Run(moves, nil)
+
+	want := []grid.Vec3{{X: 3, Y: -3, Z: 4}, {X: 3, Y: -3, Z: 3}, {X: 3, Y: -3, Z: 2}}
+	for i, knot := range rope.Knots {
+		if knot != want[i] {
+			t.Errorf("knot %d is at %v, want %v", i, knot, want[i])
+		}
+	}
+	// The last knot steps diagonally after the head once, then diagonally
+	// towards it in 3D twice
+	if got := rope.Visited(2); got != 4 {
+		t.Errorf("tail visited %d positions, want 4", got)
+	}
+}
+
+func TestTailTrailASCII(t *testing.T) {
+	moves := readTestMoves(t)
+	var out strings.Builder
+	if err := WriteASCII(&out, moves, SHORT_ROPE_LENGTH); err != nil {
+		t.Fatal(err)
+	}
+	// The final drawing of the tail's trail, as shown in the puzzle
+	want := "== Tail trail ==\n\n..##..\n...##.\n.####.\n....#.\ns###..\n"
+	if got := out.String(); !strings.HasSuffix(got, want) {
+		t.Errorf("got\n%s\nwant it to end with\n%s", got, want)
+	}
+}
+
+func decodeGIF(t *testing.T, moves []Move, options GIFOptions) *gif.GIF {
+	t.Helper()
+	var out bytes.Buffer
+	if err := WriteGIF(&out, moves, LONG_ROPE_LENGTH, options); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return anim
+}
+
+func TestGIF(t *testing.T) {
+	moves := readTestMoves(t)
+	tests := []struct {
+		every  int
+		frames int
+	}{
+		// 24 steps make a frame every 5 steps, plus the first and last frames
+		{5, 6},
+		{1, 25},
+		{24, 2},
+		{100, 2},
+	}
+	for _, test := range tests {
+		anim := decodeGIF(t, moves, GIFOptions{Scale: 3, Every: test.every, Delay: 1})
+		if len(anim.Image) != test.frames {
+			t.Errorf("every %d: got %d frames, want %d", test.every, len(anim.Image), test.frames)
+		}
+		if anim.Config.Width != 6*3 || anim.Config.Height != 5*3 {
+			t.Errorf("every %d: got a %dx%d animation, want 18x15", test.every, anim.Config.Width, anim.Config.Height)
+		}
+		if b := anim.Image[0].Bounds(); b.Dx() != 6*3 || b.Dy() != 5*3 {
+			t.Errorf("every %d: got a %dx%d first frame, want 18x15", test.every, b.Dx(), b.Dy())
+		}
+	}
+}
+
+func TestGIFOnlyDrawsChanges(t *testing.T) {
+	moves := readTestMoves(t)
+	const scale = 3
+	anim := decodeGIF(t, moves, GIFOptions{Scale: scale, Every: 1, Delay: 1})
+
+	// Draw every frame over the last, then compare with where the rope ended
+	canvas := image.NewPaletted(anim.Image[0].Bounds(), anim.Image[0].Palette)
+	for i, img := range anim.Image {
+		if i > 0 && img.Bounds().Dx()*img.Bounds().Dy() >= canvas.Bounds().Dx()*canvas.Bounds().Dy() {
+			t.Errorf("frame %d redraws the whole animation", i)
+		}
+		if anim.Disposal[i] != gif.DisposalNone {
+			t.Errorf("frame %d is disposed of with %d", i, anim.Disposal[i])
+		}
+		draw.Draw(canvas, img.Bounds(), img, img.Bounds().Min, draw.Src)
+	}
+
+	rope := NewRope(LONG_ROPE_LENGTH)
+	rope.Run(moves, nil)
+	bounds := rope.Bounds()
+	want := make(map[grid.Vec2]uint8)
+	for _, p := range rope.Trail(LONG_ROPE_LENGTH - 1) {
+		want[flatten(p)] = 1
+	}
+	want[grid.Vec2{}] = 2
+	for i := len(rope.Knots) - 1; i >= 0; i-- {
+		want[flatten(rope.Knots[i])] = uint8(3 + i*(KNOT_SHADES-1)/(len(rope.Knots)-1))
+	}
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			got := canvas.ColorIndexAt((x-bounds.Min.X)*scale, (y-bounds.Min.Y)*scale)
+			if w := want[grid.Vec2{X: x, Y: y}]; got != w {
+				t.Errorf("tile %d,%d is drawn with colour %d, want %d", x, y, got, w)
+			}
+		}
+	}
+}
+
+func TestGIFFrameBudget(t *testing.T) {
+	moves := []Move{{Direction: DIRECTIONS['R'], Count: 10 * GIF_FRAMES}, {Direction: DIRECTIONS['U'], Count: 7}}
+	anim := decodeGIF(t, moves, DefaultGIFOptions)
+	if len(anim.Image) > GIF_FRAMES+2 {
+		t.Errorf("got %d frames, want at most %d", len(anim.Image), GIF_FRAMES+2)
+	}
+
+	var out bytes.Buffer
+	if err := WriteGIF(&out, moves, LONG_ROPE_LENGTH, GIFOptions{Scale: 100, Every: 1}); err == nil {
+		t.Error("drew a GIF wider than the format allows")
+	}
+}
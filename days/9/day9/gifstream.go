@@ -0,0 +1,146 @@
+package day9
+
+import (
+	"bufio"
+	"compress/lzw"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// The largest width or height a GIF can have
+const GIF_MAX_SIZE = 1<<16 - 1
+
+// gifStream writes an animated GIF one frame at a time, unlike gif.EncodeAll,
+// which needs every frame in memory at once. Every frame is drawn over the
+// ones before it (gif.DisposalNone) and shares the same palette.
+type gifStream struct {
+	w *bufio.Writer
+	// The number of bits needed for a colour index, as the LZW code size
+	litWidth int
+	err      error
+}
+
+func newGIFStream(w io.Writer, width int, height int, pal color.Palette) (*gifStream, error) {
+	if width < 1 || height < 1 || width > GIF_MAX_SIZE || height > GIF_MAX_SIZE {
+		return nil, fmt.Errorf("can't make a %dx%d GIF", width, height)
+	}
+	if len(pal) > 256 {
+		return nil, fmt.Errorf("a GIF palette can't have %d colours", len(pal))
+	}
+	// The colour table's size is a power of two, at least 2
+	sizeBits := 0
+	for 1<<(sizeBits+1) < len(pal) {
+		sizeBits++
+	}
+	s := &gifStream{w: bufio.NewWriter(w), litWidth: sizeBits + 1}
+	if s.litWidth < 2 {
+		s.litWidth = 2
+	}
+
+	s.write([]byte("GIF89a"))
+	s.writeUint16(width, height)
+	// A global colour table with 8 bits per primary colour, background colour
+	// 0 and square pixels
+	s.write([]byte{0x80 | 0x70 | byte(sizeBits), 0, 0})
+	table := make([]byte, 3<<(sizeBits+1))
+	for i, c := range pal {
+		r, g, b, _ := c.RGBA()
+		table[3*i], table[3*i+1], table[3*i+2] = byte(r>>8), byte(g>>8), byte(b>>8)
+	}
+	s.write(table)
+	// Loop forever
+	s.write([]byte{0x21, 0xff, 0x0b})
+	s.write([]byte("NETSCAPE2.0"))
+	s.write([]byte{0x03, 0x01, 0x00, 0x00, 0x00})
+	return s, s.err
+}
+
+func (s *gifStream) write(b []byte) {
+	if s.err == nil {
+		_, s.err = s.w.Write(b)
+	}
+}
+
+func (s *gifStream) writeUint16(values ...int) {
+	for _, v := range values {
+		s.write(binary.LittleEndian.AppendUint16(nil, uint16(v)))
+	}
+}
+
+// WriteFrame draws img at its position on the canvas, shown for delay
+// hundredths of a second
+func (s *gifStream) WriteFrame(img *image.Paletted, delay int) error {
+	r := img.Rect
+	// A graphic control extension with disposal method 1 (leave the frame in
+	// place) and no transparency
+	s.write([]byte{0x21, 0xf9, 0x04, 0x01 << 2})
+	s.writeUint16(delay)
+	s.write([]byte{0x00, 0x00})
+	// The image descriptor, without a local colour table
+	s.write([]byte{0x2c})
+	s.writeUint16(r.Min.X, r.Min.Y, r.Dx(), r.Dy())
+	s.write([]byte{0x00, byte(s.litWidth)})
+	if s.err != nil {
+		return s.err
+	}
+
+	blocks := &blockWriter{w: s}
+	compressor := lzw.NewWriter(blocks, lzw.LSB, s.litWidth)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		start := img.PixOffset(r.Min.X, y)
+		if _, err := compressor.Write(img.Pix[start : start+r.Dx()]); err != nil {
+			return err
+		}
+	}
+	if err := compressor.Close(); err != nil {
+		return err
+	}
+	blocks.close()
+	return s.err
+}
+
+// Close ends the animation. It doesn't close the underlying writer.
+func (s *gifStream) Close() error {
+	s.write([]byte{0x3b})
+	if s.err != nil {
+		return s.err
+	}
+	return s.w.Flush()
+}
+
+// blockWriter splits the compressed image data into sub-blocks of at most 255
+// bytes, each preceded by its length
+type blockWriter struct {
+	w   *gifStream
+	buf [256]byte
+	n   int
+}
+
+func (b *blockWriter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		b.n++
+		b.buf[b.n] = c
+		if b.n == 255 {
+			b.flush()
+		}
+	}
+	return len(p), b.w.err
+}
+
+func (b *blockWriter) flush() {
+	if b.n == 0 {
+		return
+	}
+	b.buf[0] = byte(b.n)
+	b.w.write(b.buf[:b.n+1])
+	b.n = 0
+}
+
+// close writes what is left, followed by the empty block that ends the data
+func (b *blockWriter) close() {
+	b.flush()
+	b.w.write([]byte{0x00})
+}
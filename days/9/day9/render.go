@@ -0,0 +1,293 @@
+package day9
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+)
+
+// The default size of each tile in pixels, number of steps between frames (0
+// picks one to fit within GIF_FRAMES), and delay between frames in hundredths
+// of a second for animations
+const GIF_SCALE = 4
+const GIF_EVERY = 0
+const GIF_DELAY = 2
+
+// The most frames an animation has unless every step between frames is given
+const GIF_FRAMES = 1000
+
+type GIFOptions struct {
+	Scale int
+	// Only draw a frame every this many steps, or 0 to skip as many as it
+	// takes to keep to GIF_FRAMES
+	Every int
+	Delay int
+}
+
+var DefaultGIFOptions = GIFOptions{Scale: GIF_SCALE, Every: GIF_EVERY, Delay: GIF_DELAY}
+
+// Bounds returns the smallest box containing every position any knot has
+// visited, seen from above (ignoring Z)
+func (r *Rope) Bounds() grid.Box {
+	bounds := grid.EmptyBox()
+	for _, trail := range r.trails {
+		for p := range trail {
+			bounds = bounds.Extend(flatten(p))
+		}
+	}
+	return bounds
+}
+
+func flatten(p grid.Vec3) grid.Vec2 {
+	return grid.Vec2{X: p.X, Y: p.Y}
+}
+
+// knotSymbol draws the head as H and the other knots by their index, as the
+// puzzle does, falling back to * for knots past 9
+func knotSymbol(knot int) byte {
+	switch {
+	case knot == 0:
+		{
+			return 'H'
+		}
+	case knot < 10:
+		{
+			return byte('0' + knot)
+		}
+	default:
+		{
+			return '*'
+		}
+	}
+}
+
+// Render draws the part of the rope within bounds as the puzzle does, seen
+// from above, with knots nearer the head drawn over the ones behind them and
+// the starting position marked s. If trail is a knot's index, the positions
+// that knot has visited are drawn as # instead of the rope.
+func (r *Rope) Render(bounds grid.Box, trail int) string {
+	tiles := make(map[grid.Vec2]byte)
+	if trail >= 0 {
+		for p := range r.trails[trail] {
+			tiles[flatten(p)] = '#'
+		}
+		tiles[grid.Vec2{}] = 's'
+	} else {
+		tiles[grid.Vec2{}] = 's'
+		for i := len(r.Knots) - 1; i >= 0; i-- {
+			tiles[flatten(r.Knots[i])] = knotSymbol(i)
+		}
+	}
+
+	var b strings.Builder
+	for y := bounds.Min.Y; y <= bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x <= bounds.Max.X; x++ {
+			if c, ok := tiles[grid.Vec2{X: x, Y: y}]; ok {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// simulatedBounds runs the moves on a separate rope to find out how much room
+// the animation will need
+func simulatedBounds(moves []Move, length int) grid.Box {
+	rope := NewRope(length)
+	rope.Run(moves, nil)
+	return rope.Bounds()
+}
+
+// WriteASCII plays the moves back, drawing the rope before the first move and
+// after each one, and finally the tail's trail
+func WriteASCII(w io.Writer, moves []Move, length int) error {
+	bounds := simulatedBounds(moves, length)
+	rope := NewRope(length)
+
+	if _, err := fmt.Fprintf(w, "== Initial State ==\n\n%s\n", rope.Render(bounds, -1)); err != nil {
+		return err
+	}
+	var err error
+	rope.Run(moves, func(move int, step int) {
+		if err != nil || step != moves[move].Count {
+			return
+		}
+		_, err = fmt.Fprintf(w, "== %s ==\n\n%s\n", moves[move], rope.Render(bounds, -1))
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "== Tail trail ==\n\n%s", rope.Render(bounds, length-1))
+	return err
+}
+
+// The colours of the animation. Knots are shaded from the head to the tail.
+var (
+	backgroundColour = color.RGBA{0x0f, 0x0f, 0x23, 0xff}
+	trailColour      = color.RGBA{0x44, 0x44, 0x66, 0xff}
+	startColour      = color.RGBA{0x00, 0xcc, 0x00, 0xff}
+	headColour       = color.RGBA{0xff, 0x40, 0x40, 0xff}
+	tailColour       = color.RGBA{0xff, 0xff, 0x66, 0xff}
+)
+
+const KNOT_SHADES = 16
+
+func palette() color.Palette {
+	p := color.Palette{backgroundColour, trailColour, startColour}
+	for i := 0; i < KNOT_SHADES; i++ {
+		p = append(p, blend(headColour, tailColour, float64(i)/(KNOT_SHADES-1)))
+	}
+	return p
+}
+
+func blend(a color.RGBA, b color.RGBA, t float64) color.RGBA {
+	mix := func(x uint8, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
+
+// WriteGIF plays the moves back as an animated GIF seen from above, with the
+// tail's trail building up behind the rope. After the first frame, each frame
+// only covers the tiles that changed since the one before, and frames are
+// written as they are drawn, so long animations don't have to fit in memory.
+func WriteGIF(w io.Writer, moves []Move, length int, options GIFOptions) error {
+	if options.Scale < 1 || options.Every < 0 || options.Delay < 0 {
+		return fmt.Errorf("invalid animation options %+v", options)
+	}
+	bounds := simulatedBounds(moves, length)
+	every := options.Every
+	if every == 0 {
+		steps := 0
+		for _, move := range moves {
+			steps += move.Count
+		}
+		every = (steps + GIF_FRAMES - 1) / GIF_FRAMES
+		if every < 1 {
+			every = 1
+		}
+	}
+	if bounds.Width() > GIF_MAX_SIZE/options.Scale || bounds.Height() > GIF_MAX_SIZE/options.Scale {
+		return fmt.Errorf("the rope covers %dx%d tiles, too many to draw at %d pixels each", bounds.Width(), bounds.Height(), options.Scale)
+	}
+
+	pal := palette()
+	out, err := newGIFStream(w, bounds.Width()*options.Scale, bounds.Height()*options.Scale, pal)
+	if err != nil {
+		return err
+	}
+
+	rope := NewRope(length)
+	trail := map[grid.Vec2]bool{{}: true}
+	// The colour each tile was last drawn with, leaving out the background
+	shown := make(map[grid.Vec2]uint8)
+	// The knots as of the last frame, and trail tiles added since then
+	var lastKnots map[grid.Vec2]uint8
+	added := make([]grid.Vec2, 0)
+
+	knotColours := func() map[grid.Vec2]uint8 {
+		knots := make(map[grid.Vec2]uint8, len(rope.Knots))
+		// Knots nearer the head are drawn over the ones behind them
+		for i := len(rope.Knots) - 1; i >= 0; i-- {
+			shade := 0
+			if len(rope.Knots) > 1 {
+				shade = i * (KNOT_SHADES - 1) / (len(rope.Knots) - 1)
+			}
+			knots[flatten(rope.Knots[i])] = uint8(3 + shade)
+		}
+		return knots
+	}
+	colourAt := func(p grid.Vec2, knots map[grid.Vec2]uint8) uint8 {
+		if c, ok := knots[p]; ok {
+			return c
+		}
+		if p == (grid.Vec2{}) {
+			return 2
+		}
+		if trail[p] {
+			return 1
+		}
+		return 0
+	}
+
+	frame := func(changed grid.Box, knots map[grid.Vec2]uint8) error {
+		x0, y0 := (changed.Min.X-bounds.Min.X)*options.Scale, (changed.Min.Y-bounds.Min.Y)*options.Scale
+		img := image.NewPaletted(image.Rect(x0, y0, x0+changed.Width()*options.Scale, y0+changed.Height()*options.Scale), pal)
+		for ty := changed.Min.Y; ty <= changed.Max.Y; ty++ {
+			for tx := changed.Min.X; tx <= changed.Max.X; tx++ {
+				p := grid.Vec2{X: tx, Y: ty}
+				c := colourAt(p, knots)
+				if c == 0 {
+					delete(shown, p)
+					continue
+				}
+				shown[p] = c
+				px, py := (tx-bounds.Min.X)*options.Scale, (ty-bounds.Min.Y)*options.Scale
+				for y := py; y < py+options.Scale; y++ {
+					start := img.PixOffset(px, y)
+					for x := start; x < start+options.Scale; x++ {
+						img.Pix[x] = c
+					}
+				}
+			}
+		}
+		lastKnots = knots
+		added = added[:0]
+		return out.WriteFrame(img, options.Delay)
+	}
+	// nextFrame draws the smallest box covering every tile that looks
+	// different since the last frame
+	nextFrame := func() error {
+		knots := knotColours()
+		changed := grid.EmptyBox()
+		check := func(p grid.Vec2) {
+			if colourAt(p, knots) != shown[p] {
+				changed = changed.Extend(p)
+			}
+		}
+		for p := range lastKnots {
+			check(p)
+		}
+		for p := range knots {
+			check(p)
+		}
+		for _, p := range added {
+			check(p)
+		}
+		if changed.IsEmpty() {
+			// Nothing moved, but the frame still takes its time
+			changed = grid.BoundingBox(flatten(rope.Knots[0]))
+		}
+		return frame(changed, knots)
+	}
+
+	if err := frame(bounds, knotColours()); err != nil {
+		return err
+	}
+	rope.Run(moves, func(move int, step int) {
+		if err != nil {
+			return
+		}
+		if tail := flatten(rope.Knots[length-1]); !trail[tail] {
+			trail[tail] = true
+			added = append(added, tail)
+		}
+		if rope.Steps()%every == 0 {
+			err = nextFrame()
+		}
+	})
+	if err == nil && rope.Steps()%every != 0 {
+		err = nextFrame()
+	}
+	if err != nil {
+		return err
+	}
+	return out.Close()
+}
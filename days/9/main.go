@@ -1,168 +1,86 @@
 package main
 
 import (
-	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
-)
-
-const ROPE_LENGTH = 10
-
-type Move string
 
-const (
-	Right Move = "R"
-	Left  Move = "L"
-	Up    Move = "U"
-	Down  Move = "D"
+	"github.com/FaideWW/aoc-2022/days/9/day9"
+	"github.com/FaideWW/aoc-2022/solver"
 )
 
-type Position struct {
-	x int
-	y int
-}
-
-func check(e error) {
-	if e != nil {
-		panic(e)
-	}
-}
-
 func main() {
-	input := strings.TrimSpace(readInputFile(os.Args[1]))
-	moves := parseInput(input)
-	tilesSeen := executeMoves(moves)
-	fmt.Printf("unique tiles seen: %d\n", tilesSeen)
-}
-
-func readInputFile(filename string) string {
-	dat, err := os.ReadFile(filename)
-	check(err)
-	return string(dat)
-}
+	length := flag.Int("length", 0, "simulate a rope with this many knots, and print how many positions each knot visits")
+	ascii := flag.Bool("ascii", false, "draw the rope after every move")
+	animation := flag.String("gif", "", "write an animation of the rope to this file")
+	scale := flag.Int("scale", day9.GIF_SCALE, "the size of each tile in the animation, in pixels")
+	every := flag.Int("every", day9.GIF_EVERY, fmt.Sprintf("only draw a frame of the animation every this many steps (0 skips enough to keep to %d frames)", day9.GIF_FRAMES))
+	delay := flag.Int("delay", day9.GIF_DELAY, "the delay between frames of the animation, in hundredths of a second")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [--length n] [--ascii | --gif file] <input file>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
 
-func parseInput(input string) []Move {
-	lines := strings.Split(input, "\n")
-	moves := make([]Move, 0)
-	for _, line := range lines {
-		parts := strings.Split(line, " ")
-		amt, _ := strconv.Atoi(parts[1])
+	if *length == 0 && !*ascii && *animation == "" {
+		solver.Main(solver.Day{Part1: day9.Part1, Part2: day9.Part2})
+		return
+	}
 
-		for j := 0; j < amt; j++ {
-			var move Move
-			switch parts[0] {
-			case "R":
-				{
-					move = Right
-				}
-			case "L":
-				{
-					move = Left
-				}
-			case "U":
-				{
-					move = Up
-				}
-			case "D":
-				{
-					move = Down
-				}
-			default:
-				{
-					panic(errors.New("invalid move"))
-				}
-			}
-			moves = append(moves, move)
-		}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *length == 0 {
+		*length = day9.LONG_ROPE_LENGTH
+	}
+	if *length < 1 {
+		fmt.Fprintf(os.Stderr, "a rope needs at least one knot, not %d\n", *length)
+		os.Exit(2)
+	}
+	options := day9.GIFOptions{Scale: *scale, Every: *every, Delay: *delay}
+	if err := run(flag.Arg(0), *length, *ascii, *animation, options); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-	return moves
 }
 
-func executeMoves(moves []Move) int {
-	start := Position{x: 0, y: 0}
-	ropeNodes := make([]Position, ROPE_LENGTH)
-	for i := 0; i < len(ropeNodes); i++ {
-		ropeNodes[i] = start
+func run(path string, length int, ascii bool, animation string, options day9.GIFOptions) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
 	}
-
-	tailSeenTiles := make(map[Position]bool, 0)
-	tailSeenTiles[start] = true
-	uniqueTiles := 1
-
-	for _, move := range moves {
-		ropeNodes[0] = doMove(ropeNodes[0], move)
-		for i := 1; i < len(ropeNodes); i++ {
-			shouldMove, newPos := shouldTailMove(ropeNodes[i-1], ropeNodes[i])
-			if shouldMove {
-				ropeNodes[i] = newPos
-			}
-		}
-		tailPos := ropeNodes[len(ropeNodes)-1]
-		if !tailSeenTiles[tailPos] {
-			tailSeenTiles[tailPos] = true
-			uniqueTiles++
-		}
+	defer f.Close()
+	moves, err := day9.ReadMoves(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
 	}
 
-	return uniqueTiles
-}
-
-func doMove(pos Position, move Move) Position {
-	switch move {
-	case Right:
+	switch {
+	case ascii:
 		{
-			return Position{x: pos.x + 1, y: pos.y}
+			return day9.WriteASCII(os.Stdout, moves, length)
 		}
-	case Left:
+	case animation != "":
 		{
-			return Position{x: pos.x - 1, y: pos.y}
-		}
-	case Up:
-		{
-			return Position{x: pos.x, y: pos.y - 1}
-		}
-	case Down:
-		{
-			return Position{x: pos.x, y: pos.y + 1}
+			out, err := os.Create(animation)
+			if err != nil {
+				return err
+			}
+			err = day9.WriteGIF(out, moves, length, options)
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+			return err
 		}
 	default:
 		{
-			panic(errors.New("invalid move"))
+			rope := day9.NewRope(length)
+			rope.Run(moves, nil)
+			for knot := range rope.Knots {
+				fmt.Printf("knot %d: %d\n", knot, rope.Visited(knot))
+			}
+			return nil
 		}
 	}
 }
-
-func shouldTailMove(head Position, tail Position) (bool, Position) {
-	// First check if the tail and head overlap
-	if head == tail {
-		return false, tail
-	}
-
-	direction := Position{x: head.x - tail.x, y: head.y - tail.y}
-	shouldMove := false
-	if direction.y > 1 {
-		direction.y = 1
-		shouldMove = true
-	}
-	if direction.y < -1 {
-		direction.y = -1
-		shouldMove = true
-	}
-	if direction.x > 1 {
-		direction.x = 1
-		shouldMove = true
-	}
-	if direction.x < -1 {
-		direction.x = -1
-		shouldMove = true
-	}
-
-	nextTail := tail
-	if shouldMove {
-		nextTail = Position{x: tail.x + direction.x, y: tail.y + direction.y}
-	}
-
-	return shouldMove, nextTail
-}
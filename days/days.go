@@ -0,0 +1,68 @@
+// Package days collects the solvers for every day of the calendar.
+package days
+
+import (
+	"github.com/FaideWW/aoc-2022/days/1/day1"
+	"github.com/FaideWW/aoc-2022/days/10/day10"
+	"github.com/FaideWW/aoc-2022/days/11/day11"
+	"github.com/FaideWW/aoc-2022/days/12/day12"
+	"github.com/FaideWW/aoc-2022/days/13/day13"
+	"github.com/FaideWW/aoc-2022/days/14/day14"
+	"github.com/FaideWW/aoc-2022/days/15/day15"
+	"github.com/FaideWW/aoc-2022/days/16/day16"
+	"github.com/FaideWW/aoc-2022/days/17/day17"
+	"github.com/FaideWW/aoc-2022/days/18/day18"
+	"github.com/FaideWW/aoc-2022/days/19/day19"
+	"github.com/FaideWW/aoc-2022/days/2/day2"
+	"github.com/FaideWW/aoc-2022/days/20/day20"
+	"github.com/FaideWW/aoc-2022/days/21/day21"
+	"github.com/FaideWW/aoc-2022/days/22/day22"
+	"github.com/FaideWW/aoc-2022/days/23/day23"
+	"github.com/FaideWW/aoc-2022/days/24/day24"
+	"github.com/FaideWW/aoc-2022/days/25/day25"
+	"github.com/FaideWW/aoc-2022/days/3/day3"
+	"github.com/FaideWW/aoc-2022/days/4/day4"
+	"github.com/FaideWW/aoc-2022/days/5/day5"
+	"github.com/FaideWW/aoc-2022/days/6/day6"
+	"github.com/FaideWW/aoc-2022/days/7/day7"
+	"github.com/FaideWW/aoc-2022/days/8/day8"
+	"github.com/FaideWW/aoc-2022/days/9/day9"
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+// Solvers holds the solver for each day, indexed from day 1
+var Solvers = []solver.Solver{
+	solver.Day{Part1: day1.Part1, Part2: day1.Part2},
+	solver.Day{Part1: day2.Part1, Part2: day2.Part2},
+	solver.Day{Part1: day3.Part1, Part2: day3.Part2},
+	solver.Day{Part1: day4.Part1, Part2: day4.Part2},
+	solver.Day{Part1: day5.Part1, Part2: day5.Part2},
+	solver.Day{Part1: day6.Part1, Part2: day6.Part2},
+	solver.Day{Part1: day7.Part1, Part2: day7.Part2},
+	solver.Day{Part1: day8.Part1, Part2: day8.Part2},
+	solver.Day{Part1: day9.Part1, Part2: day9.Part2},
+	solver.Day{Part1: day10.Part1, Part2: day10.Part2},
+	solver.Day{Part1: day11.Part1, Part2: day11.Part2},
+	solver.Day{Part1: day12.Part1, Part2: day12.Part2},
+	solver.Day{Part1: day13.Part1, Part2: day13.Part2},
+	solver.Day{Part1: day14.Part1, Part2: day14.Part2},
+	solver.Day{Part1: day15.Part1, Part2: day15.Part2},
+	solver.Day{Part1: day16.Part1, Part2: day16.Part2},
+	solver.Day{Part1: day17.Part1, Part2: day17.Part2},
+	solver.Day{Part1: day18.Part1, Part2: day18.Part2},
+	solver.Day{Part1: day19.Part1, Part2: day19.Part2},
+	solver.Day{Part1: day20.Part1, Part2: day20.Part2},
+	solver.Day{Part1: day21.Part1, Part2: day21.Part2},
+	solver.Day{Part1: day22.Part1, Part2: day22.Part2},
+	solver.Day{Part1: day23.Part1, Part2: day23.Part2},
+	solver.Day{Part1: day24.Part1, Part2: day24.Part2},
+	solver.Day{Part1: day25.Part1},
+}
+
+// Get returns the solver for the given day of the calendar
+func Get(day int) (solver.Solver, bool) {
+	if day < 1 || day > len(Solvers) {
+		return nil, false
+	}
+	return Solvers[day-1], true
+}
@@ -0,0 +1,146 @@
+package days
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FaideWW/aoc-2022/solver"
+)
+
+var update = flag.Bool("update", false, "regenerate the .expected files from the current solvers")
+
+// Days whose example inputs take long enough to solve that they are skipped
+// with -short
+var slowDays = map[int]bool{
+	19: true,
+}
+
+// expected holds the answers to a fixture. Parts that are missing (because the
+// day has no such part, or the fixture is only meant for the other part) are
+// not checked.
+type expected struct {
+	Part1 *string `json:"part1,omitempty"`
+	Part2 *string `json:"part2,omitempty"`
+}
+
+func (e *expected) part(n int) **string {
+	if n == 1 {
+		return &e.Part1
+	}
+	return &e.Part2
+}
+
+// Every .txt file in a day's directory other than input.txt is an example
+// input, with its answers stored next to it in a .expected file
+func findFixtures(t *testing.T, day int) []string {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(fmt.Sprint(day), "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if filepath.Base(path) != "input.txt" {
+			fixtures = append(fixtures, path)
+		}
+	}
+	return fixtures
+}
+
+func expectedPath(fixture string) string {
+	return strings.TrimSuffix(fixture, filepath.Ext(fixture)) + ".expected"
+}
+
+func TestGolden(t *testing.T) {
+	for i, s := range Solvers {
+		day := i + 1
+		s := s
+		for _, fixture := range findFixtures(t, day) {
+			fixture := fixture
+			t.Run(filepath.ToSlash(fixture), func(t *testing.T) {
+				if testing.Short() && slowDays[day] {
+					t.Skip("slow example; skipped in short mode")
+				}
+				t.Parallel()
+
+				input, err := os.ReadFile(fixture)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if *update {
+					updateExpected(t, fixture, s, input)
+					return
+				}
+
+				want := readExpected(t, fixture)
+				for part := 1; part <= 2; part++ {
+					answer := *want.part(part)
+					if answer == nil {
+						continue
+					}
+
+					got, err := s.Solve(part, strings.NewReader(string(input)))
+					if err != nil {
+						t.Errorf("part %d: unexpected error: %s", part, err)
+						continue
+					}
+					if got != *answer {
+						t.Errorf("part %d: got %q, want %q", part, got, *answer)
+					}
+				}
+			})
+		}
+	}
+}
+
+func readExpected(t *testing.T, fixture string) expected {
+	t.Helper()
+
+	var want expected
+	dat, err := os.ReadFile(expectedPath(fixture))
+	if os.IsNotExist(err) {
+		t.Fatalf("no expected answers for %s; run go test ./days -update to generate them", fixture)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(dat, &want); err != nil {
+		t.Fatalf("%s: %s", expectedPath(fixture), err)
+	}
+	if want.Part1 == nil && want.Part2 == nil {
+		t.Fatalf("%s: no expected answers, so the example checks nothing", expectedPath(fixture))
+	}
+	return want
+}
+
+// updateExpected records the current answers as the expected ones. Parts that
+// fail are left out of the file, so they won't be checked.
+func updateExpected(t *testing.T, fixture string, s solver.Solver, input []byte) {
+	t.Helper()
+
+	var answers expected
+	for part := 1; part <= 2; part++ {
+		got, err := s.Solve(part, strings.NewReader(string(input)))
+		if err != nil {
+			t.Logf("part %d: not recording an answer: %s", part, err)
+			continue
+		}
+		*answers.part(part) = &got
+	}
+
+	dat, err := json.MarshalIndent(answers, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(expectedPath(fixture), append(dat, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,57 @@
+package days
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+// Malformed inputs must be reported as a parse.Error pointing at the problem,
+// rather than panicking or producing an answer
+func TestMalformedInput(t *testing.T) {
+	tests := []struct {
+		day   int
+		part  int
+		input string
+		line  int
+		col   int
+	}{
+		{day: 1, part: 1, input: "1000\n2x00\n\n3000\n", line: 2, col: 1},
+		{day: 2, part: 1, input: "A Y\nB Q\n", line: 2, col: 3},
+		{day: 3, part: 1, input: "vJrwpWtwJgWr\nabc1ef\n", line: 2, col: 4},
+		{day: 3, part: 2, input: "vJrwpWtwJgWr\n   \nabcdef\n", line: 2, col: 1},
+		{day: 4, part: 1, input: "2-4,6-8\n2-4,6:8\n", line: 2, col: 5},
+		{day: 5, part: 1, input: "    [D]    \n[N] [C]    \n[Z] [M] [P]\n 1   2   3 \n\nmove 1 from 4 to 1\n", line: 6, col: 13},
+		{day: 6, part: 1, input: "abcDefgh\n", line: 1, col: 4},
+		{day: 7, part: 1, input: "$ cd /\n$ ls\n1 x\n$ ls\n2 x\n", line: 5, col: 1},
+		{day: 8, part: 1, input: "303\n2x5\n", line: 2, col: 2},
+		{day: 9, part: 1, input: "R 4\nQ 2\n", line: 2, col: 1},
+		{day: 10, part: 1, input: "noop\naddx ten\n", line: 2, col: 6},
+		{day: 11, part: 1, input: "Monkey 0:\n  Starting items: 1\n  Operation: new = old * (old + 2\n  Test: divisible by 2\n    If true: throw to monkey 1\n    If false: throw to monkey 1\n", line: 3, col: 34},
+		{day: 12, part: 1, input: "Sab\nab!\nccE\n", line: 2, col: 3},
+		{day: 13, part: 1, input: "[1,2]\n[1,,2]\n", line: 2, col: 4},
+		{day: 14, part: 1, input: "498,4 -> 498,6\n503,4 -> 502,x\n", line: 2, col: 14},
+		{day: 18, part: 1, input: "1,1,1\n2,1\n", line: 2, col: 1},
+		{day: 20, part: 1, input: "1\n2\n-3\nthree\n0\n", line: 4, col: 1},
+		{day: 21, part: 1, input: "root: aaaa + bbbb\naaaa: 5\nbbbb: cccc * 2\n", line: 3, col: 7},
+		{day: 25, part: 1, input: "1=-0-2\n12x\n", line: 2, col: 3},
+	}
+
+	for _, test := range tests {
+		got, err := Solvers[test.day-1].Solve(test.part, strings.NewReader(test.input))
+		if err == nil {
+			t.Errorf("day %d: got answer %q, want an error", test.day, got)
+			continue
+		}
+		var parseErr *parse.Error
+		if !errors.As(err, &parseErr) {
+			t.Errorf("day %d: got %q, want a parse error", test.day, err)
+			continue
+		}
+		if parseErr.Line != test.line || parseErr.Col != test.col {
+			t.Errorf("day %d: got %q, want it at line %d, col %d", test.day, err, test.line, test.col)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package grid
+
+import "math"
+
+// Box is an axis-aligned bounding box. Both corners are inclusive.
+type Box struct {
+	Min Vec2
+	Max Vec2
+}
+
+// EmptyBox returns a box containing nothing, ready to be extended
+func EmptyBox() Box {
+	return Box{
+		Min: Vec2{math.MaxInt, math.MaxInt},
+		Max: Vec2{math.MinInt, math.MinInt},
+	}
+}
+
+// BoundingBox returns the smallest box containing every point
+func BoundingBox(points ...Vec2) Box {
+	b := EmptyBox()
+	for _, p := range points {
+		b = b.Extend(p)
+	}
+	return b
+}
+
+func (b Box) IsEmpty() bool {
+	return b.Min.X > b.Max.X || b.Min.Y > b.Max.Y
+}
+
+// Extend returns the smallest box containing both b and p
+func (b Box) Extend(p Vec2) Box {
+	return Box{
+		Min: Vec2{minInt(b.Min.X, p.X), minInt(b.Min.Y, p.Y)},
+		Max: Vec2{maxInt(b.Max.X, p.X), maxInt(b.Max.Y, p.Y)},
+	}
+}
+
+// Union returns the smallest box containing both b and o
+func (b Box) Union(o Box) Box {
+	if o.IsEmpty() {
+		return b
+	}
+	if b.IsEmpty() {
+		return o
+	}
+	return b.Extend(o.Min).Extend(o.Max)
+}
+
+// Intersect returns the tiles in both b and o, which is empty if they don't
+// overlap
+func (b Box) Intersect(o Box) Box {
+	i := Box{
+		Min: Vec2{maxInt(b.Min.X, o.Min.X), maxInt(b.Min.Y, o.Min.Y)},
+		Max: Vec2{minInt(b.Max.X, o.Max.X), minInt(b.Max.Y, o.Max.Y)},
+	}
+	if i.IsEmpty() {
+		return EmptyBox()
+	}
+	return i
+}
+
+// Grow returns b expanded by n tiles on every side
+func (b Box) Grow(n int) Box {
+	return Box{
+		Min: Vec2{b.Min.X - n, b.Min.Y - n},
+		Max: Vec2{b.Max.X + n, b.Max.Y + n},
+	}
+}
+
+func (b Box) Contains(p Vec2) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X && p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+func (b Box) Width() int {
+	if b.IsEmpty() {
+		return 0
+	}
+	return b.Max.X - b.Min.X + 1
+}
+
+func (b Box) Height() int {
+	if b.IsEmpty() {
+		return 0
+	}
+	return b.Max.Y - b.Min.Y + 1
+}
+
+func (b Box) Area() int {
+	return b.Width() * b.Height()
+}
+
+// Box3 is an axis-aligned bounding box in 3D. Both corners are inclusive.
+type Box3 struct {
+	Min Vec3
+	Max Vec3
+}
+
+// EmptyBox3 returns a box containing nothing, ready to be extended
+func EmptyBox3() Box3 {
+	return Box3{
+		Min: Vec3{math.MaxInt, math.MaxInt, math.MaxInt},
+		Max: Vec3{math.MinInt, math.MinInt, math.MinInt},
+	}
+}
+
+func (b Box3) IsEmpty() bool {
+	return b.Min.X > b.Max.X || b.Min.Y > b.Max.Y || b.Min.Z > b.Max.Z
+}
+
+// Extend returns the smallest box containing both b and p
+func (b Box3) Extend(p Vec3) Box3 {
+	return Box3{
+		Min: Vec3{minInt(b.Min.X, p.X), minInt(b.Min.Y, p.Y), minInt(b.Min.Z, p.Z)},
+		Max: Vec3{maxInt(b.Max.X, p.X), maxInt(b.Max.Y, p.Y), maxInt(b.Max.Z, p.Z)},
+	}
+}
+
+// Grow returns b expanded by n cubes on every side
+func (b Box3) Grow(n int) Box3 {
+	return Box3{
+		Min: Vec3{b.Min.X - n, b.Min.Y - n, b.Min.Z - n},
+		Max: Vec3{b.Max.X + n, b.Max.Y + n, b.Max.Z + n},
+	}
+}
+
+func (b Box3) Contains(p Vec3) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X &&
+		p.Y >= b.Min.Y && p.Y <= b.Max.Y &&
+		p.Z >= b.Min.Z && p.Z <= b.Max.Z
+}
@@ -0,0 +1,87 @@
+package grid
+
+import "testing"
+
+func box(minX, minY, maxX, maxY int) Box {
+	return Box{Min: Vec2{minX, minY}, Max: Vec2{maxX, maxY}}
+}
+
+func TestBoxUnion(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Box
+		want Box
+	}{
+		{"overlapping", box(0, 0, 2, 2), box(1, 1, 4, 3), box(0, 0, 4, 3)},
+		{"disjoint", box(0, 0, 1, 1), box(5, -3, 6, -2), box(0, -3, 6, 1)},
+		{"nested", box(-5, -5, 5, 5), box(0, 0, 1, 1), box(-5, -5, 5, 5)},
+		{"single tiles", box(2, 2, 2, 2), box(3, 0, 3, 0), box(2, 0, 3, 2)},
+		{"with empty", box(1, 2, 3, 4), EmptyBox(), box(1, 2, 3, 4)},
+		{"empty with", EmptyBox(), box(1, 2, 3, 4), box(1, 2, 3, 4)},
+		{"both empty", EmptyBox(), EmptyBox(), EmptyBox()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.a.Union(test.b); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			if got := test.b.Union(test.a); got != test.want {
+				t.Errorf("reversed: got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBoxIntersect(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Box
+		want Box
+	}{
+		{"overlapping", box(0, 0, 2, 2), box(1, 1, 4, 3), box(1, 1, 2, 2)},
+		{"nested", box(-5, -5, 5, 5), box(0, 0, 1, 1), box(0, 0, 1, 1)},
+		{"touching edge", box(0, 0, 2, 2), box(2, 0, 4, 2), box(2, 0, 2, 2)},
+		{"touching corner", box(0, 0, 2, 2), box(2, 2, 4, 4), box(2, 2, 2, 2)},
+		{"adjacent", box(0, 0, 2, 2), box(3, 0, 4, 2), EmptyBox()},
+		{"overlapping in x only", box(0, 0, 2, 2), box(1, 5, 4, 6), EmptyBox()},
+		{"with empty", box(1, 2, 3, 4), EmptyBox(), EmptyBox()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.a.Intersect(test.b); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			if got := test.b.Intersect(test.a); got != test.want {
+				t.Errorf("reversed: got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBoxSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		b             Box
+		width, height int
+		empty         bool
+	}{
+		{"empty", EmptyBox(), 0, 0, true},
+		{"single tile", box(3, 3, 3, 3), 1, 1, false},
+		{"negative corner", box(-2, -1, 2, 1), 5, 3, false},
+		{"inverted", box(2, 0, 1, 5), 0, 0, true},
+		{"bounding points", BoundingBox(Vec2{1, 5}, Vec2{-1, 2}, Vec2{0, 0}), 3, 6, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.b.IsEmpty() != test.empty {
+				t.Errorf("got IsEmpty %v, want %v", test.b.IsEmpty(), test.empty)
+			}
+			if test.b.Width() != test.width || test.b.Height() != test.height {
+				t.Errorf("got %dx%d, want %dx%d", test.b.Width(), test.b.Height(), test.width, test.height)
+			}
+			if test.b.Area() != test.width*test.height {
+				t.Errorf("got area %d, want %d", test.b.Area(), test.width*test.height)
+			}
+		})
+	}
+}
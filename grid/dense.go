@@ -0,0 +1,202 @@
+package grid
+
+import (
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/parse"
+)
+
+// Dense is a rectangular grid with a value for every tile, starting at {0, 0}
+type Dense[T any] struct {
+	width  int
+	height int
+	cells  []T
+}
+
+func NewDense[T any](width int, height int) *Dense[T] {
+	return &Dense[T]{
+		width:  width,
+		height: height,
+		cells:  make([]T, width*height),
+	}
+}
+
+// Parse reads a character map into a dense grid, converting each character
+// with convert. The grid is as wide as the longest line; tiles past the end of
+// shorter lines are left as the zero value.
+func Parse[T any](input string, convert func(p Vec2, c byte) T) *Dense[T] {
+	lines := strings.Split(strings.TrimRight(input, "\n"), "\n")
+	width := 0
+	for _, line := range lines {
+		width = maxInt(width, len(line))
+	}
+
+	g := NewDense[T](width, len(lines))
+	for y, line := range lines {
+		for x := 0; x < len(line); x++ {
+			p := Vec2{x, y}
+			g.Set(p, convert(p, line[x]))
+		}
+	}
+	return g
+}
+
+// ParseStrict reads a character map into a dense grid like Parse, but
+// requires every line to be the same width and lets convert reject
+// characters. Errors are reported at the line and column of the offending
+// character.
+func ParseStrict[T any](input string, convert func(p Vec2, c byte) (T, error)) (*Dense[T], error) {
+	lines := parse.Lines(input)
+	width := len(lines[0].Text)
+	for _, line := range lines {
+		if len(line.Text) != width {
+			return nil, line.Errorf("expected %d columns, got %d", width, len(line.Text))
+		}
+	}
+
+	g := NewDense[T](width, len(lines))
+	for y, line := range lines {
+		for x := 0; x < len(line.Text); x++ {
+			p := Vec2{x, y}
+			value, err := convert(p, line.Text[x])
+			if err != nil {
+				return nil, &parse.Error{Line: line.Line, Col: line.Col + x, Err: err}
+			}
+			g.Set(p, value)
+		}
+	}
+	return g, nil
+}
+
+func (g *Dense[T]) Width() int {
+	return g.width
+}
+
+func (g *Dense[T]) Height() int {
+	return g.height
+}
+
+func (g *Dense[T]) Bounds() Box {
+	return Box{Min: Vec2{0, 0}, Max: Vec2{g.width - 1, g.height - 1}}
+}
+
+func (g *Dense[T]) InBounds(p Vec2) bool {
+	return p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+// At returns the value at p, which must be in bounds
+func (g *Dense[T]) At(p Vec2) T {
+	if !g.InBounds(p) {
+		panic("grid: position out of bounds")
+	}
+	return g.cells[p.Y*g.width+p.X]
+}
+
+// Get returns the value at p, or false if p is out of bounds
+func (g *Dense[T]) Get(p Vec2) (T, bool) {
+	if !g.InBounds(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[p.Y*g.width+p.X], true
+}
+
+// Set stores a value at p, which must be in bounds
+func (g *Dense[T]) Set(p Vec2, value T) {
+	if !g.InBounds(p) {
+		panic("grid: position out of bounds")
+	}
+	g.cells[p.Y*g.width+p.X] = value
+}
+
+// Neighbours4 returns the in-bounds orthogonal neighbours of p
+func (g *Dense[T]) Neighbours4(p Vec2) []Vec2 {
+	return g.inBounds(p.Neighbours4())
+}
+
+// Neighbours8 returns the in-bounds orthogonal and diagonal neighbours of p
+func (g *Dense[T]) Neighbours8(p Vec2) []Vec2 {
+	return g.inBounds(p.Neighbours8())
+}
+
+func (g *Dense[T]) inBounds(positions []Vec2) []Vec2 {
+	filtered := positions[:0]
+	for _, p := range positions {
+		if g.InBounds(p) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// RotateCW returns a copy of the grid turned a quarter turn clockwise
+func (g *Dense[T]) RotateCW() *Dense[T] {
+	rotated := NewDense[T](g.height, g.width)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			rotated.Set(Vec2{g.height - 1 - y, x}, g.At(Vec2{x, y}))
+		}
+	}
+	return rotated
+}
+
+// RotateCCW returns a copy of the grid turned a quarter turn counter-clockwise
+func (g *Dense[T]) RotateCCW() *Dense[T] {
+	rotated := NewDense[T](g.height, g.width)
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			rotated.Set(Vec2{y, g.width - 1 - x}, g.At(Vec2{x, y}))
+		}
+	}
+	return rotated
+}
+
+// Dense3 is a cuboid grid with a value for every cube, starting at {0, 0, 0}
+type Dense3[T any] struct {
+	size  Vec3
+	cells []T
+}
+
+func NewDense3[T any](width int, height int, depth int) *Dense3[T] {
+	return &Dense3[T]{
+		size:  Vec3{width, height, depth},
+		cells: make([]T, width*height*depth),
+	}
+}
+
+func (g *Dense3[T]) Bounds() Box3 {
+	return Box3{Min: Vec3{0, 0, 0}, Max: g.size.Sub(Vec3{1, 1, 1})}
+}
+
+func (g *Dense3[T]) InBounds(p Vec3) bool {
+	return p.X >= 0 && p.X < g.size.X && p.Y >= 0 && p.Y < g.size.Y && p.Z >= 0 && p.Z < g.size.Z
+}
+
+func (g *Dense3[T]) index(p Vec3) int {
+	return (p.Z*g.size.Y+p.Y)*g.size.X + p.X
+}
+
+// At returns the value at p, which must be in bounds
+func (g *Dense3[T]) At(p Vec3) T {
+	if !g.InBounds(p) {
+		panic("grid: position out of bounds")
+	}
+	return g.cells[g.index(p)]
+}
+
+// Get returns the value at p, or false if p is out of bounds
+func (g *Dense3[T]) Get(p Vec3) (T, bool) {
+	if !g.InBounds(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[g.index(p)], true
+}
+
+// Set stores a value at p, which must be in bounds
+func (g *Dense3[T]) Set(p Vec3, value T) {
+	if !g.InBounds(p) {
+		panic("grid: position out of bounds")
+	}
+	g.cells[g.index(p)] = value
+}
@@ -0,0 +1,100 @@
+package grid
+
+import "testing"
+
+func TestDenseBounds(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		want          Box
+		empty         bool
+	}{
+		{"square", 3, 3, box(0, 0, 2, 2), false},
+		{"wide", 5, 1, box(0, 0, 4, 0), false},
+		{"single tile", 1, 1, box(0, 0, 0, 0), false},
+		{"no rows", 4, 0, box(0, 0, 3, -1), true},
+		{"nothing", 0, 0, box(0, 0, -1, -1), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewDense[int](test.width, test.height)
+			b := g.Bounds()
+			if b != test.want {
+				t.Errorf("got %v, want %v", b, test.want)
+			}
+			if b.IsEmpty() != test.empty {
+				t.Errorf("got IsEmpty %v, want %v", b.IsEmpty(), test.empty)
+			}
+			if b.Area() != test.width*test.height {
+				t.Errorf("got area %d, want %d", b.Area(), test.width*test.height)
+			}
+		})
+	}
+}
+
+func TestDenseOutOfRange(t *testing.T) {
+	g := Parse("123\n456\n", func(p Vec2, c byte) int { return int(c - '0') })
+	tests := []struct {
+		p    Vec2
+		want int
+		ok   bool
+	}{
+		{Vec2{0, 0}, 1, true},
+		{Vec2{2, 1}, 6, true},
+		{Vec2{-1, 0}, 0, false},
+		{Vec2{0, -1}, 0, false},
+		{Vec2{3, 0}, 0, false},
+		{Vec2{0, 2}, 0, false},
+		// In range of the cells, but past the end of the row
+		{Vec2{3, 1}, 0, false},
+		{Vec2{-1, 1}, 0, false},
+	}
+	for _, test := range tests {
+		if g.InBounds(test.p) != test.ok {
+			t.Errorf("InBounds(%v) = %v, want %v", test.p, !test.ok, test.ok)
+		}
+		if got, ok := g.Get(test.p); got != test.want || ok != test.ok {
+			t.Errorf("Get(%v) = %d, %v, want %d, %v", test.p, got, ok, test.want, test.ok)
+		}
+		if test.ok {
+			if got := g.At(test.p); got != test.want {
+				t.Errorf("At(%v) = %d, want %d", test.p, got, test.want)
+			}
+			continue
+		}
+		for name, access := range map[string]func(){
+			"At":  func() { g.At(test.p) },
+			"Set": func() { g.Set(test.p, 9) },
+		} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s(%v) didn't panic", name, test.p)
+					}
+				}()
+				access()
+			}()
+		}
+	}
+}
+
+func TestDenseNeighbours(t *testing.T) {
+	g := NewDense[int](3, 2)
+	tests := []struct {
+		p      Vec2
+		n4, n8 int
+	}{
+		{Vec2{0, 0}, 2, 3},
+		{Vec2{1, 0}, 3, 5},
+		{Vec2{1, 1}, 3, 5},
+		{Vec2{2, 1}, 2, 3},
+	}
+	for _, test := range tests {
+		if got := len(g.Neighbours4(test.p)); got != test.n4 {
+			t.Errorf("%v has %d orthogonal neighbours, want %d", test.p, got, test.n4)
+		}
+		if got := len(g.Neighbours8(test.p)); got != test.n8 {
+			t.Errorf("%v has %d neighbours, want %d", test.p, got, test.n8)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package grid
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"math"
+)
+
+// The shade of every pixel in a heatmap of a grid with only one value
+const FLAT_SHADE = 128
+
+// Heatmap draws the grid as a grayscale image with one pixel per tile, shaded
+// by value from black for the lowest value in the grid to white for the
+// highest. If every tile has the same value, the image is mid grey.
+func Heatmap[T any](g *Dense[T], value func(T) float64) *image.Gray {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	values := make([]float64, len(g.cells))
+	for i, cell := range g.cells {
+		v := value(cell)
+		values[i] = v
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	img := image.NewGray(image.Rect(0, 0, g.width, g.height))
+	if hi <= lo {
+		// Every tile is the same, so there is nothing to shade
+		for i := range img.Pix {
+			img.Pix[i] = FLAT_SHADE
+		}
+		return img
+	}
+	for i, v := range values {
+		// The image's rows are laid out the same way as the grid's
+		img.Pix[i] = uint8(math.Round((v - lo) / (hi - lo) * 255))
+	}
+	return img
+}
+
+// WritePGM writes the image as a binary ("P5") portable graymap
+func WritePGM(w io.Writer, img *image.Gray) error {
+	bounds := img.Bounds()
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "P5\n%d %d\n255\n", bounds.Dx(), bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		start := img.PixOffset(bounds.Min.X, y)
+		if _, err := out.Write(img.Pix[start : start+bounds.Dx()]); err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
@@ -0,0 +1,43 @@
+package grid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHeatmap(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []uint8
+	}{
+		{"range", "012\n345\n", []uint8{0, 51, 102, 153, 204, 255}},
+		{"two values", "00\n90\n", []uint8{0, 0, 255, 0}},
+		{"flat", "77\n77\n", []uint8{FLAT_SHADE, FLAT_SHADE, FLAT_SHADE, FLAT_SHADE}},
+		{"single tile", "3\n", []uint8{FLAT_SHADE}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := Parse(test.input, func(p Vec2, c byte) int { return int(c - '0') })
+			img := Heatmap(g, func(v int) float64 { return float64(v) })
+			if img.Bounds().Dx() != g.Width() || img.Bounds().Dy() != g.Height() {
+				t.Fatalf("got a %v image for a %dx%d grid", img.Bounds(), g.Width(), g.Height())
+			}
+			if !bytes.Equal(img.Pix, test.want) {
+				t.Errorf("got %v, want %v", img.Pix, test.want)
+			}
+		})
+	}
+}
+
+func TestWritePGM(t *testing.T) {
+	g := Parse("01\n23\n45\n", func(p Vec2, c byte) int { return int(c - '0') })
+	var b bytes.Buffer
+	if err := WritePGM(&b, Heatmap(g, func(v int) float64 { return float64(v) })); err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte("P5\n2 3\n255\n"), 0, 51, 102, 153, 204, 255)
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Errorf("got %q, want %q", b.Bytes(), want)
+	}
+}
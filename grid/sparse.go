@@ -0,0 +1,36 @@
+package grid
+
+import "strings"
+
+// Sparse is a grid that only stores the tiles that have been set, and can grow
+// in any direction
+type Sparse[T any] map[Vec2]T
+
+// ParseSparse reads a character map into a sparse grid. convert returns false
+// for characters that should be left out of the grid.
+func ParseSparse[T any](input string, convert func(p Vec2, c byte) (T, bool)) Sparse[T] {
+	g := make(Sparse[T])
+	for y, line := range strings.Split(input, "\n") {
+		for x := 0; x < len(line); x++ {
+			p := Vec2{x, y}
+			if value, ok := convert(p, line[x]); ok {
+				g[p] = value
+			}
+		}
+	}
+	return g
+}
+
+func (g Sparse[T]) Has(p Vec2) bool {
+	_, ok := g[p]
+	return ok
+}
+
+// Bounds returns the smallest box containing every tile in the grid
+func (g Sparse[T]) Bounds() Box {
+	b := EmptyBox()
+	for p := range g {
+		b = b.Extend(p)
+	}
+	return b
+}
@@ -0,0 +1,34 @@
+package grid
+
+import "testing"
+
+func TestSparseBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Box
+	}{
+		{"empty", "...\n...", EmptyBox()},
+		{"single tile", "...\n.#.", box(1, 1, 1, 1)},
+		{"spread out", "#..\n...\n..#.", box(0, 0, 2, 2)},
+		{"ragged lines", ".\n....#\n#", box(0, 1, 4, 2)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := ParseSparse(test.input, func(p Vec2, c byte) (bool, bool) { return true, c == '#' })
+			if got := g.Bounds(); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	g := make(Sparse[bool])
+	g[Vec2{-3, 7}] = true
+	g[Vec2{4, -2}] = true
+	if got, want := g.Bounds(), box(-3, -2, 4, 7); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if !g.Has(Vec2{-3, 7}) || g.Has(Vec2{0, 0}) {
+		t.Error("Has doesn't match the tiles that were set")
+	}
+}
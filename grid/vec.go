@@ -0,0 +1,183 @@
+// Package grid provides the integer vectors, bounding boxes and grids shared
+// by the days whose puzzles take place on a map.
+//
+// Y increases downwards, matching the way the puzzle maps are drawn, so Up is
+// {0, -1} and rotating clockwise turns Up into Right.
+package grid
+
+type Vec2 struct {
+	X int
+	Y int
+}
+
+var (
+	Up    = Vec2{0, -1}
+	Right = Vec2{1, 0}
+	Down  = Vec2{0, 1}
+	Left  = Vec2{-1, 0}
+)
+
+// Directions4 holds the orthogonal directions, clockwise from Up
+var Directions4 = []Vec2{Up, Right, Down, Left}
+
+// Directions8 holds the orthogonal and diagonal directions, clockwise from Up
+var Directions8 = []Vec2{
+	Up, Up.Add(Right), Right, Down.Add(Right), Down, Down.Add(Left), Left, Up.Add(Left),
+}
+
+func (v Vec2) Add(o Vec2) Vec2 {
+	return Vec2{v.X + o.X, v.Y + o.Y}
+}
+
+func (v Vec2) Sub(o Vec2) Vec2 {
+	return Vec2{v.X - o.X, v.Y - o.Y}
+}
+
+func (v Vec2) Times(s int) Vec2 {
+	return Vec2{v.X * s, v.Y * s}
+}
+
+// Sign clamps each component to -1, 0 or 1, giving a single step towards v
+func (v Vec2) Sign() Vec2 {
+	return Vec2{sign(v.X), sign(v.Y)}
+}
+
+// Manhattan returns the taxicab length of v
+func (v Vec2) Manhattan() int {
+	return abs(v.X) + abs(v.Y)
+}
+
+// Chebyshev returns the length of v when diagonal steps are allowed
+func (v Vec2) Chebyshev() int {
+	return maxInt(abs(v.X), abs(v.Y))
+}
+
+func (v Vec2) RotateCW() Vec2 {
+	return Vec2{-v.Y, v.X}
+}
+
+func (v Vec2) RotateCCW() Vec2 {
+	return Vec2{v.Y, -v.X}
+}
+
+// Neighbours4 returns the orthogonally adjacent positions, clockwise from Up
+func (v Vec2) Neighbours4() []Vec2 {
+	return v.offsets(Directions4)
+}
+
+// Neighbours8 returns the orthogonally and diagonally adjacent positions,
+// clockwise from Up
+func (v Vec2) Neighbours8() []Vec2 {
+	return v.offsets(Directions8)
+}
+
+func (v Vec2) offsets(directions []Vec2) []Vec2 {
+	neighbours := make([]Vec2, len(directions))
+	for i, d := range directions {
+		neighbours[i] = v.Add(d)
+	}
+	return neighbours
+}
+
+type Vec3 struct {
+	X int
+	Y int
+	Z int
+}
+
+// Directions6 holds the face-adjacent directions in 3D
+var Directions6 = []Vec3{
+	{-1, 0, 0}, {1, 0, 0}, {0, -1, 0}, {0, 1, 0}, {0, 0, -1}, {0, 0, 1},
+}
+
+// Directions26 holds every direction to a face, edge or corner-adjacent cube
+var Directions26 = func() []Vec3 {
+	directions := make([]Vec3, 0, 26)
+	for x := -1; x <= 1; x++ {
+		for y := -1; y <= 1; y++ {
+			for z := -1; z <= 1; z++ {
+				if x != 0 || y != 0 || z != 0 {
+					directions = append(directions, Vec3{x, y, z})
+				}
+			}
+		}
+	}
+	return directions
+}()
+
+func (v Vec3) Add(o Vec3) Vec3 {
+	return Vec3{v.X + o.X, v.Y + o.Y, v.Z + o.Z}
+}
+
+func (v Vec3) Sub(o Vec3) Vec3 {
+	return Vec3{v.X - o.X, v.Y - o.Y, v.Z - o.Z}
+}
+
+func (v Vec3) Times(s int) Vec3 {
+	return Vec3{v.X * s, v.Y * s, v.Z * s}
+}
+
+// Sign clamps each component to -1, 0 or 1, giving a single step towards v
+func (v Vec3) Sign() Vec3 {
+	return Vec3{sign(v.X), sign(v.Y), sign(v.Z)}
+}
+
+// Manhattan returns the taxicab length of v
+func (v Vec3) Manhattan() int {
+	return abs(v.X) + abs(v.Y) + abs(v.Z)
+}
+
+// Chebyshev returns the length of v when diagonal steps are allowed
+func (v Vec3) Chebyshev() int {
+	return maxInt(abs(v.X), maxInt(abs(v.Y), abs(v.Z)))
+}
+
+// Neighbours6 returns the positions sharing a face with v
+func (v Vec3) Neighbours6() []Vec3 {
+	return v.offsets(Directions6)
+}
+
+// Neighbours26 returns the positions sharing a face, edge or corner with v
+func (v Vec3) Neighbours26() []Vec3 {
+	return v.offsets(Directions26)
+}
+
+func (v Vec3) offsets(directions []Vec3) []Vec3 {
+	neighbours := make([]Vec3, len(directions))
+	for i, d := range directions {
+		neighbours[i] = v.Add(d)
+	}
+	return neighbours
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
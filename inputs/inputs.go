@@ -0,0 +1,153 @@
+// Package inputs downloads puzzle inputs from Advent of Code and caches them
+// on disk, so each input is only ever fetched once.
+package inputs
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const YEAR = 2022
+
+const (
+	// SESSION_ENV holds the value of the adventofcode.com session cookie
+	SESSION_ENV = "AOC_SESSION"
+	// BASE_URL_ENV overrides the site to download from (e.g. a local stub server)
+	BASE_URL_ENV = "AOC_BASE_URL"
+	// DIR_ENV overrides the directory inputs are cached under
+	DIR_ENV = "AOC_INPUT_DIR"
+)
+
+const DEFAULT_BASE_URL = "https://adventofcode.com"
+
+// DEFAULT_DIR keeps inputs next to each day's solution, at days/<day>/input.txt
+const DEFAULT_DIR = "days"
+
+// USER_AGENT identifies this tool to adventofcode.com, as the site asks of
+// automated requests
+const USER_AGENT = "github.com/FaideWW/aoc-2022"
+
+// ErrNoSession is returned when an input has to be downloaded but no session
+// cookie was provided
+var ErrNoSession = fmt.Errorf("%s is not set", SESSION_ENV)
+
+// Client fetches puzzle inputs, preferring copies already cached in Dir
+type Client struct {
+	BaseURL    string
+	Session    string
+	Dir        string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client configured from the environment
+func NewClient() *Client {
+	return &Client{
+		BaseURL:    envOr(BASE_URL_ENV, DEFAULT_BASE_URL),
+		Session:    os.Getenv(SESSION_ENV),
+		Dir:        envOr(DIR_ENV, DEFAULT_DIR),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+func envOr(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// Path returns where a day's input is cached
+func (c *Client) Path(day int) string {
+	return filepath.Join(c.Dir, strconv.Itoa(day), "input.txt")
+}
+
+// IsCached reports whether a day's input has already been downloaded
+func (c *Client) IsCached(day int) bool {
+	_, err := os.Stat(c.Path(day))
+	return err == nil
+}
+
+// Get returns the path to a day's input, downloading it first if it isn't
+// cached yet
+func (c *Client) Get(day int) (string, error) {
+	if c.IsCached(day) {
+		return c.Path(day), nil
+	}
+	return c.Download(day)
+}
+
+// Download fetches a day's input, replacing any cached copy, and returns the
+// path it was saved to
+func (c *Client) Download(day int) (string, error) {
+	if c.Session == "" {
+		return "", fmt.Errorf("day %d input is not cached at %s and %w", day, c.Path(day), ErrNoSession)
+	}
+
+	url := fmt.Sprintf("%s/%d/day/%d/input", strings.TrimRight(c.BaseURL, "/"), YEAR, day)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", USER_AGENT)
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading day %d input: %s: %s", day, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if len(body) == 0 {
+		return "", fmt.Errorf("downloading day %d input: empty response", day)
+	}
+
+	path := c.Path(day)
+	if err := writeFile(path, body); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeFile writes through a temporary file, so an interrupted download never
+// leaves a truncated input behind in the cache
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".input-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("caching input at %s: %w", path, err)
+	}
+	return nil
+}
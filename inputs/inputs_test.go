@@ -0,0 +1,78 @@
+package inputs
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// stubServer serves a fixed input for day 1 and counts the requests it sees
+func stubServer(t *testing.T, requests *int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.URL.Path != "/2022/day/1/input" {
+			http.NotFound(w, r)
+			return
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "secret" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+		if r.UserAgent() != USER_AGENT {
+			t.Errorf("User-Agent = %q, want %q", r.UserAgent(), USER_AGENT)
+		}
+		w.Write([]byte("1000\n2000\n"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetDownloadsOnce(t *testing.T) {
+	requests := 0
+	server := stubServer(t, &requests)
+	c := &Client{BaseURL: server.URL, Session: "secret", Dir: t.TempDir()}
+
+	for i := 0; i < 2; i++ {
+		path, err := c.Get(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dat, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(dat) != "1000\n2000\n" {
+			t.Errorf("cached input = %q", dat)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("server saw %d requests, want 1", requests)
+	}
+}
+
+func TestDownloadErrors(t *testing.T) {
+	requests := 0
+	server := stubServer(t, &requests)
+
+	c := &Client{BaseURL: server.URL, Dir: t.TempDir()}
+	if _, err := c.Get(1); !errors.Is(err, ErrNoSession) {
+		t.Errorf("without a session: got %v, want ErrNoSession", err)
+	}
+
+	c.Session = "wrong"
+	if _, err := c.Get(1); err == nil {
+		t.Error("with a bad session: expected an error")
+	}
+
+	c.Session = "secret"
+	if _, err := c.Get(2); err == nil {
+		t.Error("for a missing day: expected an error")
+	}
+
+	if c.IsCached(1) || c.IsCached(2) {
+		t.Error("failed downloads should not be cached")
+	}
+}
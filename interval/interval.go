@@ -0,0 +1,220 @@
+// Package interval works with closed ranges of integers, and sets of them kept
+// as sorted, non-overlapping intervals.
+package interval
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Interval is the closed range [Min, Max]. It is empty if Max < Min.
+type Interval struct {
+	Min int
+	Max int
+}
+
+// Empty is an interval containing nothing
+var Empty = Interval{Min: 0, Max: -1}
+
+func (i Interval) IsEmpty() bool {
+	return i.Max < i.Min
+}
+
+// Len returns the number of integers in the interval
+func (i Interval) Len() int {
+	if i.IsEmpty() {
+		return 0
+	}
+	return i.Max - i.Min + 1
+}
+
+func (i Interval) Contains(v int) bool {
+	return i.Min <= v && v <= i.Max
+}
+
+// Covers returns whether every integer in other is also in i
+func (i Interval) Covers(other Interval) bool {
+	return other.IsEmpty() || (i.Min <= other.Min && other.Max <= i.Max)
+}
+
+func (i Interval) Overlaps(other Interval) bool {
+	return !i.Intersect(other).IsEmpty()
+}
+
+// Intersect returns the integers in both intervals, which may be Empty
+func (i Interval) Intersect(other Interval) Interval {
+	result := Interval{Min: maxInt(i.Min, other.Min), Max: minInt(i.Max, other.Max)}
+	if result.IsEmpty() {
+		return Empty
+	}
+	return result
+}
+
+func (i Interval) String() string {
+	if i.IsEmpty() {
+		return "[]"
+	}
+	return fmt.Sprintf("[%d, %d]", i.Min, i.Max)
+}
+
+// Set is a set of integers, stored as the sorted, disjoint intervals that make
+// it up. Intervals that touch are merged, so [1, 2] and [3, 4] are kept as
+// [1, 4]. The zero value is an empty set.
+type Set struct {
+	intervals []Interval
+}
+
+// NewSet returns a set containing every integer in the given intervals
+func NewSet(intervals ...Interval) *Set {
+	s := &Set{}
+	for _, i := range intervals {
+		s.Insert(i)
+	}
+	return s
+}
+
+// Intervals returns the intervals making up the set, in order
+func (s *Set) Intervals() []Interval {
+	intervals := make([]Interval, len(s.intervals))
+	copy(intervals, s.intervals)
+	return intervals
+}
+
+func (s *Set) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// Len returns the number of integers in the set
+func (s *Set) Len() int {
+	total := 0
+	for _, i := range s.intervals {
+		total += i.Len()
+	}
+	return total
+}
+
+// Bounds returns the smallest interval containing the whole set
+func (s *Set) Bounds() Interval {
+	if s.IsEmpty() {
+		return Empty
+	}
+	return Interval{Min: s.intervals[0].Min, Max: s.intervals[len(s.intervals)-1].Max}
+}
+
+// find returns the index of the first interval that ends at or after v
+func (s *Set) find(v int) int {
+	return sort.Search(len(s.intervals), func(i int) bool {
+		return s.intervals[i].Max >= v
+	})
+}
+
+func (s *Set) Contains(v int) bool {
+	i := s.find(v)
+	return i < len(s.intervals) && s.intervals[i].Contains(v)
+}
+
+// Covers returns whether every integer in the interval is in the set
+func (s *Set) Covers(interval Interval) bool {
+	if interval.IsEmpty() {
+		return true
+	}
+	i := s.find(interval.Min)
+	return i < len(s.intervals) && s.intervals[i].Covers(interval)
+}
+
+// Insert adds the integers in the interval to the set, merging it with any
+// intervals it overlaps or touches
+func (s *Set) Insert(interval Interval) {
+	if interval.IsEmpty() {
+		return
+	}
+
+	// The intervals from start up to (not including) end overlap or touch the
+	// new one
+	start := s.find(interval.Min - 1)
+	end := start
+	for end < len(s.intervals) && s.intervals[end].Min <= interval.Max+1 {
+		end++
+	}
+	if start < end {
+		interval.Min = minInt(interval.Min, s.intervals[start].Min)
+		interval.Max = maxInt(interval.Max, s.intervals[end-1].Max)
+	}
+
+	s.splice(start, end, interval)
+}
+
+// Merge adds every integer in other to the set
+func (s *Set) Merge(other *Set) {
+	for _, i := range other.intervals {
+		s.Insert(i)
+	}
+}
+
+// Subtract removes the integers in the interval from the set
+func (s *Set) Subtract(interval Interval) {
+	if interval.IsEmpty() {
+		return
+	}
+
+	start := s.find(interval.Min)
+	end := start
+	for end < len(s.intervals) && s.intervals[end].Min <= interval.Max {
+		end++
+	}
+	if start == end {
+		return
+	}
+
+	// Keep whatever sticks out either side of the removed interval
+	remaining := make([]Interval, 0, 2)
+	if first := s.intervals[start]; first.Min < interval.Min {
+		remaining = append(remaining, Interval{Min: first.Min, Max: interval.Min - 1})
+	}
+	if last := s.intervals[end-1]; last.Max > interval.Max {
+		remaining = append(remaining, Interval{Min: interval.Max + 1, Max: last.Max})
+	}
+	s.splice(start, end, remaining...)
+}
+
+// splice replaces the intervals from start up to (not including) end
+func (s *Set) splice(start int, end int, replacements ...Interval) {
+	tail := append([]Interval{}, s.intervals[end:]...)
+	s.intervals = append(append(s.intervals[:start], replacements...), tail...)
+}
+
+// Gaps returns the intervals missing between the start and end of the set
+func (s *Set) Gaps() []Interval {
+	gaps := make([]Interval, 0)
+	for i := 1; i < len(s.intervals); i++ {
+		gaps = append(gaps, Interval{Min: s.intervals[i-1].Max + 1, Max: s.intervals[i].Min - 1})
+	}
+	return gaps
+}
+
+// Complement returns the integers within bounds that aren't in the set
+func (s *Set) Complement(bounds Interval) *Set {
+	complement := NewSet(bounds)
+	for _, i := range s.intervals {
+		complement.Subtract(i)
+	}
+	return complement
+}
+
+func (s *Set) String() string {
+	return fmt.Sprint(s.intervals)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
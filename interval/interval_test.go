@@ -0,0 +1,62 @@
+package interval
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsertMerges(t *testing.T) {
+	s := NewSet(Interval{10, 12}, Interval{1, 3}, Interval{5, 6})
+	s.Insert(Interval{4, 4})
+	want := []Interval{{1, 6}, {10, 12}}
+	if got := s.Intervals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	s.Insert(Interval{0, 20})
+	want = []Interval{{0, 20}}
+	if got := s.Intervals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	s := NewSet(Interval{1, 10}, Interval{20, 30})
+	s.Subtract(Interval{5, 22})
+	want := []Interval{{1, 4}, {23, 30}}
+	if got := s.Intervals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if s.Len() != 12 {
+		t.Errorf("Len() = %d, want 12", s.Len())
+	}
+	if s.Contains(5) || !s.Contains(4) || !s.Contains(23) {
+		t.Errorf("wrong membership for %v", s)
+	}
+}
+
+func TestGapsAndComplement(t *testing.T) {
+	s := NewSet(Interval{2, 3}, Interval{6, 8}, Interval{10, 10})
+
+	wantGaps := []Interval{{4, 5}, {9, 9}}
+	if got := s.Gaps(); !reflect.DeepEqual(got, wantGaps) {
+		t.Errorf("Gaps() = %v, want %v", got, wantGaps)
+	}
+
+	want := []Interval{{0, 1}, {4, 5}, {9, 9}}
+	if got := s.Complement(Interval{0, 9}).Intervals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Complement() = %v, want %v", got, want)
+	}
+	if !s.Covers(Interval{6, 8}) || s.Covers(Interval{6, 9}) {
+		t.Errorf("wrong coverage for %v", s)
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	if got := (Interval{2, 6}).Intersect(Interval{4, 8}); got != (Interval{4, 6}) {
+		t.Errorf("got %v, want [4, 6]", got)
+	}
+	if (Interval{2, 3}).Overlaps(Interval{4, 8}) {
+		t.Error("[2, 3] should not overlap [4, 8]")
+	}
+}
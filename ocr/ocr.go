@@ -0,0 +1,140 @@
+// Package ocr reads the capital letters that some puzzles draw as their answer,
+// in the 4x6 pixel font Advent of Code uses for them.
+package ocr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/FaideWW/aoc-2022/grid"
+)
+
+const GLYPH_WIDTH = 4
+const GLYPH_HEIGHT = 6
+
+// The blank columns between one letter and the next
+const GLYPH_SPACING = 1
+
+// Glyph is the bitmap of a single letter, as rows of # and . characters
+type Glyph [GLYPH_HEIGHT]string
+
+// The letters that have been seen in puzzle answers. Letters missing from the
+// table are reported as unknown glyphs, along with their bitmap, so that they
+// can be added.
+var GLYPHS = map[Glyph]rune{
+	{".##.", "#..#", "#..#", "####", "#..#", "#..#"}: 'A',
+	{"###.", "#..#", "###.", "#..#", "#..#", "###."}: 'B',
+	{".##.", "#..#", "#...", "#...", "#..#", ".##."}: 'C',
+	{"####", "#...", "###.", "#...", "#...", "####"}: 'E',
+	{"####", "#...", "###.", "#...", "#...", "#..."}: 'F',
+	{".##.", "#..#", "#...", "#.##", "#..#", ".###"}: 'G',
+	{"#..#", "#..#", "####", "#..#", "#..#", "#..#"}: 'H',
+	{".###", "..#.", "..#.", "..#.", "..#.", ".###"}: 'I',
+	{"..##", "...#", "...#", "...#", "#..#", ".##."}: 'J',
+	{"#..#", "#.#.", "##..", "#.#.", "#.#.", "#..#"}: 'K',
+	{"#...", "#...", "#...", "#...", "#...", "####"}: 'L',
+	{".##.", "#..#", "#..#", "#..#", "#..#", ".##."}: 'O',
+	{"###.", "#..#", "#..#", "###.", "#...", "#..."}: 'P',
+	{"###.", "#..#", "#..#", "###.", "#.#.", "#..#"}: 'R',
+	{".###", "#...", "#...", ".##.", "...#", "###."}: 'S',
+	{"#..#", "#..#", "#..#", "#..#", "#..#", ".##."}: 'U',
+	{"#...", "#...", ".#.#", "..#.", "..#.", "..#."}: 'Y',
+	{"####", "...#", "..#.", ".#..", "#...", "####"}: 'Z',
+}
+
+// blank is the glyph of a gap between words, or of the unused end of a screen
+var blank = Glyph{"....", "....", "....", "....", "....", "...."}
+
+// UnknownGlyphError is returned when a letter isn't in the table
+type UnknownGlyphError struct {
+	// The position of the letter, counting from 0
+	Index int
+	// The letter's pixels, including the gap after it if anything was drawn
+	// there
+	Glyph Glyph
+}
+
+func (e *UnknownGlyphError) Error() string {
+	return fmt.Sprintf("unknown glyph at letter %d:\n%s", e.Index+1, e.Glyph)
+}
+
+func (g Glyph) String() string {
+	return strings.Join(g[:], "\n")
+}
+
+// Letter returns the letter drawn by the glyph
+func Letter(g Glyph) (rune, bool) {
+	letter, ok := GLYPHS[g]
+	return letter, ok
+}
+
+// Read returns the letters drawn in a row across the pixels, which must be
+// exactly one letter high. Blank cells read as spaces, and are trimmed from
+// either end.
+func Read(pixels *grid.Dense[bool]) (string, error) {
+	if pixels.Height() != GLYPH_HEIGHT {
+		return "", fmt.Errorf("letters are %d pixels high, not %d", GLYPH_HEIGHT, pixels.Height())
+	}
+
+	var b strings.Builder
+	for i := 0; i*(GLYPH_WIDTH+GLYPH_SPACING) < pixels.Width(); i++ {
+		left := i * (GLYPH_WIDTH + GLYPH_SPACING)
+		// The glyph along with the gap after it, which must be blank
+		var cell Glyph
+		for y := 0; y < GLYPH_HEIGHT; y++ {
+			var row strings.Builder
+			for x := left; x < left+GLYPH_WIDTH+GLYPH_SPACING; x++ {
+				if x < pixels.Width() && pixels.At(grid.Vec2{X: x, Y: y}) {
+					row.WriteByte('#')
+				} else {
+					row.WriteByte('.')
+				}
+			}
+			cell[y] = row.String()
+		}
+
+		var glyph Glyph
+		for y, row := range cell {
+			if strings.Contains(row[GLYPH_WIDTH:], "#") {
+				return "", &UnknownGlyphError{Index: i, Glyph: cell}
+			}
+			glyph[y] = row[:GLYPH_WIDTH]
+		}
+
+		if glyph == blank {
+			b.WriteByte(' ')
+			continue
+		}
+		letter, ok := Letter(glyph)
+		if !ok {
+			return "", &UnknownGlyphError{Index: i, Glyph: glyph}
+		}
+		b.WriteRune(letter)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// ReadString is like Read, but for pixels drawn as lines of # and .
+// characters
+func ReadString(screen string) (string, error) {
+	pixels, err := grid.ParseStrict(strings.TrimSpace(screen), func(p grid.Vec2, c byte) (bool, error) {
+		switch c {
+		case '#':
+			{
+				return true, nil
+			}
+		case '.':
+			{
+				return false, nil
+			}
+		default:
+			{
+				return false, fmt.Errorf("invalid pixel %q", c)
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return Read(pixels)
+}
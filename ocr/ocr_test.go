@@ -0,0 +1,62 @@
+package ocr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// draw lays out the glyphs as the puzzles do, with a blank column after each
+func draw(glyphs ...Glyph) string {
+	var rows [GLYPH_HEIGHT]string
+	for _, g := range glyphs {
+		for y := range rows {
+			rows[y] += g[y] + "."
+		}
+	}
+	return strings.Join(rows[:], "\n")
+}
+
+func TestEveryLetter(t *testing.T) {
+	for glyph, letter := range GLYPHS {
+		got, err := ReadString(draw(blank, glyph, glyph, blank, glyph))
+		if err != nil {
+			t.Errorf("%c: %s", letter, err)
+			continue
+		}
+		if want := string([]rune{letter, letter, ' ', letter}); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestUnknownGlyph(t *testing.T) {
+	h := Glyph{"#..#", "#..#", "####", "#..#", "#..#", "#..#"}
+	mystery := Glyph{"#...", ".#..", "..#.", "...#", "..#.", ".#.."}
+
+	_, err := ReadString(draw(h, mystery))
+	var unknown *UnknownGlyphError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected an unknown glyph, got %v", err)
+	}
+	if unknown.Index != 1 || unknown.Glyph != mystery {
+		t.Errorf("got glyph %d:\n%s", unknown.Index, unknown.Glyph)
+	}
+	if !strings.Contains(err.Error(), mystery.String()) {
+		t.Errorf("error doesn't show the bitmap: %s", err)
+	}
+
+	// A letter that spills into the gap after it is reported with the gap
+	wide := strings.Replace(draw(h), "#..#.", "#..##", 1)
+	if _, err := ReadString(wide); !errors.As(err, &unknown) || unknown.Glyph[0] != "#..##" {
+		t.Errorf("expected the gap to be reported, got %v", err)
+	}
+}
+
+func TestInvalidScreens(t *testing.T) {
+	for _, screen := range []string{"####\n#..#", draw(blank) + "\n.....", strings.Replace(draw(blank), ".", "x", 1)} {
+		if _, err := ReadString(screen); err == nil {
+			t.Errorf("expected an error for\n%s", screen)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package parse helps solvers read puzzle input while keeping track of where
+// each piece came from, so that malformed input is reported with the line and
+// column at fault instead of producing a wrong answer.
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Error is a problem with the puzzle input. Line and Col are 1-based, and are
+// 0 when unknown. Path is the file the input was read from, if known; it is
+// usually filled in by whoever opened the file, since solvers only see a
+// reader.
+type Error struct {
+	Path string
+	Line int
+	Col  int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Path != "" {
+		// path:line:col, as understood by editors and terminals
+		pos := e.Path
+		if e.Line != 0 {
+			pos += fmt.Sprintf(":%d", e.Line)
+			if e.Col != 0 {
+				pos += fmt.Sprintf(":%d", e.Col)
+			}
+		}
+		return fmt.Sprintf("%s: %s", pos, e.Err)
+	}
+
+	switch {
+	case e.Line == 0:
+		return e.Err.Error()
+	case e.Col == 0:
+		return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+	default:
+		return fmt.Sprintf("line %d, col %d: %s", e.Line, e.Col, e.Err)
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Errorf returns an Error at the given position
+func Errorf(line int, col int, format string, a ...any) error {
+	return &Error{Line: line, Col: col, Err: fmt.Errorf(format, a...)}
+}
+
+// Field is a piece of the input, along with where it starts
+type Field struct {
+	Text string
+	Line int
+	Col  int
+}
+
+// Lines splits the input into lines. A trailing newline does not start an
+// extra empty line.
+func Lines(input string) []Field {
+	input = strings.TrimSuffix(input, "\n")
+	lines := strings.Split(input, "\n")
+	fields := make([]Field, len(lines))
+	for i, line := range lines {
+		fields[i] = Field{Text: strings.TrimSuffix(line, "\r"), Line: i + 1, Col: 1}
+	}
+	return fields
+}
+
+// Paragraphs splits the input into groups of lines separated by blank lines
+func Paragraphs(input string) [][]Field {
+	paragraphs := make([][]Field, 0)
+	current := make([]Field, 0)
+	for _, line := range Lines(input) {
+		if line.Text == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, current)
+			}
+			current = make([]Field, 0)
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, current)
+	}
+	return paragraphs
+}
+
+// Errorf returns an Error pointing at the start of the field
+func (f Field) Errorf(format string, a ...any) error {
+	return Errorf(f.Line, f.Col, format, a...)
+}
+
+// Slice returns the field's text from i up to (not including) j
+func (f Field) Slice(i int, j int) Field {
+	return Field{Text: f.Text[i:j], Line: f.Line, Col: f.Col + i}
+}
+
+// From returns the field's text from i onwards
+func (f Field) From(i int) Field {
+	return f.Slice(i, len(f.Text))
+}
+
+// Split splits the field around each instance of sep
+func (f Field) Split(sep string) []Field {
+	parts := strings.Split(f.Text, sep)
+	fields := make([]Field, len(parts))
+	offset := 0
+	for i, part := range parts {
+		fields[i] = f.Slice(offset, offset+len(part))
+		offset += len(part) + len(sep)
+	}
+	return fields
+}
+
+// SplitN splits the field around sep into exactly n pieces, or returns an
+// error naming what the field should have looked like
+func (f Field) SplitN(sep string, n int, want string) ([]Field, error) {
+	fields := f.Split(sep)
+	if len(fields) != n {
+		return nil, f.Errorf("expected %s, got %q", want, f.Text)
+	}
+	return fields, nil
+}
+
+// Fields splits the field around runs of spaces
+func (f Field) Fields() []Field {
+	fields := make([]Field, 0)
+	start := -1
+	for i := 0; i <= len(f.Text); i++ {
+		if i == len(f.Text) || f.Text[i] == ' ' {
+			if start >= 0 {
+				fields = append(fields, f.Slice(start, i))
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	return fields
+}
+
+// TrimSpace removes leading and trailing spaces, keeping the column in step
+func (f Field) TrimSpace() Field {
+	trimmed := strings.TrimLeft(f.Text, " \t")
+	f = f.From(len(f.Text) - len(trimmed))
+	return f.Slice(0, len(strings.TrimRight(f.Text, " \t")))
+}
+
+// CutPrefix returns the field without prefix, or an error if it doesn't start
+// with it
+func (f Field) CutPrefix(prefix string) (Field, error) {
+	if !strings.HasPrefix(f.Text, prefix) {
+		return f, f.Errorf("expected %q, got %q", prefix, f.Text)
+	}
+	return f.From(len(prefix)), nil
+}
+
+// Match matches re against the whole field and returns the submatches, or an
+// error naming what the field should have looked like. Optional groups that
+// didn't match are returned empty.
+func (f Field) Match(re *regexp.Regexp, want string) ([]Field, error) {
+	indices := re.FindStringSubmatchIndex(f.Text)
+	if indices == nil || indices[0] != 0 || indices[1] != len(f.Text) {
+		return nil, f.Errorf("expected %s, got %q", want, f.Text)
+	}
+
+	groups := make([]Field, len(indices)/2-1)
+	for i := range groups {
+		start, end := indices[2*i+2], indices[2*i+3]
+		if start < 0 {
+			groups[i] = Field{Line: f.Line, Col: f.Col}
+			continue
+		}
+		groups[i] = f.Slice(start, end)
+	}
+	return groups, nil
+}
+
+// Int reads the field as a base 10 integer
+func (f Field) Int() (int, error) {
+	n, err := strconv.Atoi(f.Text)
+	if err != nil {
+		return 0, f.Errorf("invalid number %q", f.Text)
+	}
+	return n, nil
+}
+
+// Ints reads each of the fields as a base 10 integer
+func Ints(fields []Field) ([]int, error) {
+	ints := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := f.Int()
+		if err != nil {
+			return nil, err
+		}
+		ints[i] = n
+	}
+	return ints, nil
+}
+
+// Byte returns the byte at i, or an error if the field is too short
+func (f Field) Byte(i int) (byte, error) {
+	if i >= len(f.Text) {
+		return 0, Errorf(f.Line, f.Col+len(f.Text), "unexpected end of line")
+	}
+	return f.Text[i], nil
+}
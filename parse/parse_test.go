@@ -0,0 +1,67 @@
+package parse
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestFieldPositions(t *testing.T) {
+	lines := Lines("a: 1\r\nb:  22 333\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if lines[0].Text != "a: 1" {
+		t.Errorf("carriage return not stripped: %q", lines[0].Text)
+	}
+
+	parts, err := lines[1].SplitN(":", 2, "a name and numbers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := parts[1].Fields()
+	want := []Field{{"22", 2, 5}, {"333", 2, 8}}
+	if len(fields) != len(want) {
+		t.Fatalf("got %v, want %v", fields, want)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("field %d: got %+v, want %+v", i, fields[i], want[i])
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	re := regexp.MustCompile(`move (\d+) from (\d+)`)
+	line := Field{Text: "move 3 from x", Line: 4, Col: 1}
+	_, err := line.Match(re, "a move")
+
+	var parseErr *Error
+	if !errors.As(err, &parseErr) || parseErr.Line != 4 || parseErr.Col != 1 {
+		t.Fatalf("got %v, want an error at line 4, col 1", err)
+	}
+
+	groups, err := Field{Text: "move 3 from 12", Line: 4, Col: 1}.Match(re, "a move")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groups[1].Col != 13 {
+		t.Errorf("got col %d, want 13", groups[1].Col)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  *Error
+		want string
+	}{
+		{&Error{Line: 3, Col: 7, Err: errors.New("bad")}, "line 3, col 7: bad"},
+		{&Error{Line: 3, Err: errors.New("bad")}, "line 3: bad"},
+		{&Error{Path: "input.txt", Line: 3, Col: 7, Err: errors.New("bad")}, "input.txt:3:7: bad"},
+	}
+	for _, test := range tests {
+		if got := test.err.Error(); got != test.want {
+			t.Errorf("got %q, want %q", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package search
+
+import "container/heap"
+
+// Item is a value held in a PriorityQueue. Keep hold of it to change its
+// priority later with Update.
+type Item[T any] struct {
+	Value    T
+	priority int
+	index    int
+	// The order the item was pushed in, to break ties
+	seq int
+}
+
+// PriorityQueue is a min-heap of values ordered by an integer priority (lower
+// value == higher priority). Values with the same priority come out in the
+// order they were pushed.
+type PriorityQueue[T any] struct {
+	items  items[T]
+	pushed int
+}
+
+func NewPriorityQueue[T any]() *PriorityQueue[T] {
+	return &PriorityQueue[T]{}
+}
+
+func (pq *PriorityQueue[T]) Len() int { return len(pq.items) }
+
+// Push adds a value to the queue and returns its handle
+func (pq *PriorityQueue[T]) Push(value T, priority int) *Item[T] {
+	item := &Item[T]{Value: value, priority: priority, seq: pq.pushed}
+	pq.pushed++
+	heap.Push(&pq.items, item)
+	return item
+}
+
+// Pop removes the value with the lowest priority, and returns it alongside its
+// priority
+func (pq *PriorityQueue[T]) Pop() (T, int) {
+	item := heap.Pop(&pq.items).(*Item[T])
+	return item.Value, item.priority
+}
+
+// Peek returns the value with the lowest priority without removing it
+func (pq *PriorityQueue[T]) Peek() (T, int) {
+	item := pq.items[0]
+	return item.Value, item.priority
+}
+
+// Update changes the priority of an item that is still in the queue
+func (pq *PriorityQueue[T]) Update(item *Item[T], priority int) {
+	item.priority = priority
+	heap.Fix(&pq.items, item.index)
+}
+
+// heap.Interface implementation (https://pkg.go.dev/container/heap)
+type items[T any] []*Item[T]
+
+func (pq items[T]) Len() int { return len(pq) }
+
+func (pq items[T]) Less(i, j int) bool {
+	if pq[i].priority != pq[j].priority {
+		return pq[i].priority < pq[j].priority
+	}
+	return pq[i].seq < pq[j].seq
+}
+
+func (pq items[T]) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *items[T]) Push(x any) {
+	n := len(*pq)
+	item := x.(*Item[T])
+	item.index = n
+	*pq = append(*pq, item)
+}
+
+func (pq *items[T]) Pop() any {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*pq = old[0 : n-1]
+	return item
+}
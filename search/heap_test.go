@@ -0,0 +1,67 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func popAll[T any](pq *PriorityQueue[T]) ([]T, []int) {
+	values, priorities := make([]T, 0), make([]int, 0)
+	for pq.Len() > 0 {
+		value, priority := pq.Pop()
+		values = append(values, value)
+		priorities = append(priorities, priority)
+	}
+	return values, priorities
+}
+
+func TestPriorityQueue(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     []string
+		priorities []int
+		want       []string
+	}{
+		{"empty", nil, nil, []string{}},
+		{"ordered", []string{"a", "b", "c"}, []int{1, 2, 3}, []string{"a", "b", "c"}},
+		{"reversed", []string{"a", "b", "c"}, []int{3, 2, 1}, []string{"c", "b", "a"}},
+		{"negative", []string{"a", "b", "c"}, []int{0, -5, 5}, []string{"b", "a", "c"}},
+		{"ties in push order", []string{"a", "b", "c", "d", "e"}, []int{2, 1, 2, 1, 2}, []string{"b", "d", "a", "c", "e"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pq := NewPriorityQueue[string]()
+			for i, value := range test.values {
+				pq.Push(value, test.priorities[i])
+			}
+			if got, _ := popAll(pq); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPriorityQueueUpdate(t *testing.T) {
+	pq := NewPriorityQueue[string]()
+	pq.Push("a", 1)
+	b := pq.Push("b", 2)
+	c := pq.Push("c", 3)
+
+	pq.Update(c, 0)
+	if value, priority := pq.Peek(); value != "c" || priority != 0 {
+		t.Errorf("peeked %s at %d, want c at 0", value, priority)
+	}
+	if pq.Len() != 3 {
+		t.Errorf("got length %d after peeking, want 3", pq.Len())
+	}
+
+	// Updating to a tie keeps the order the items were pushed in
+	pq.Update(b, 1)
+	values, priorities := popAll(pq)
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("got %v, want %v", values, want)
+	}
+	if want := []int{0, 1, 1}; !reflect.DeepEqual(priorities, want) {
+		t.Errorf("got priorities %v, want %v", priorities, want)
+	}
+}
@@ -0,0 +1,189 @@
+package search
+
+// Edge is a connection to a neighbouring node, and what it costs to take it
+type Edge[N comparable] struct {
+	To   N
+	Cost int
+}
+
+// Graph is anything that can list the edges leaving a node. Nodes are
+// discovered lazily, so the graph never has to be built up front.
+type Graph[N comparable] interface {
+	Edges(n N) []Edge[N]
+}
+
+// GraphFunc adapts an ordinary function into a weighted Graph
+type GraphFunc[N comparable] func(n N) []Edge[N]
+
+func (f GraphFunc[N]) Edges(n N) []Edge[N] {
+	return f(n)
+}
+
+// Unweighted adapts a function listing a node's neighbours into a Graph where
+// every edge costs 1
+type Unweighted[N comparable] func(n N) []N
+
+func (f Unweighted[N]) Edges(n N) []Edge[N] {
+	neighbours := f(n)
+	edges := make([]Edge[N], len(neighbours))
+	for i, neighbour := range neighbours {
+		edges[i] = Edge[N]{To: neighbour, Cost: 1}
+	}
+	return edges
+}
+
+// Heuristic estimates the remaining cost from a node to the goal. For A* to
+// find the shortest path it must never overestimate.
+type Heuristic[N comparable] func(n N) int
+
+// Result holds everything a search learned about the graph
+type Result[N comparable] struct {
+	// Costs holds the cheapest known cost to every node the search reached
+	Costs map[N]int
+	// Goal is the first goal node reached, if Found is true
+	Goal  N
+	Found bool
+
+	cameFrom map[N]N
+}
+
+// Cost returns the cost of the cheapest path to n, and false if the search
+// never reached it
+func (r Result[N]) Cost(n N) (int, bool) {
+	cost, ok := r.Costs[n]
+	return cost, ok
+}
+
+// Path reconstructs the path from a start node to n (both inclusive). It
+// returns nil if the search never reached n.
+func (r Result[N]) Path(n N) []N {
+	if _, ok := r.Costs[n]; !ok {
+		return nil
+	}
+
+	path := []N{n}
+	for {
+		last, ok := r.cameFrom[n]
+		if !ok {
+			break
+		}
+		path = append(path, last)
+		n = last
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func newResult[N comparable]() Result[N] {
+	return Result[N]{
+		Costs:    make(map[N]int),
+		cameFrom: make(map[N]N),
+	}
+}
+
+// Dijkstra finds the cheapest path from any of the starts to a node matching
+// isGoal. If isGoal is nil, the search explores every reachable node.
+func Dijkstra[N comparable](g Graph[N], starts []N, isGoal func(N) bool) Result[N] {
+	return AStar(g, starts, isGoal, nil)
+}
+
+// AStar finds the cheapest path from any of the starts to a node matching
+// isGoal, exploring the nodes that heuristic rates as closest to the goal
+// first. A nil heuristic makes this Dijkstra's algorithm. A node that is later
+// reached more cheaply is explored again, so the heuristic only has to be
+// admissible; a consistent one (that never drops by more than the cost of an
+// edge) means no node is explored twice.
+func AStar[N comparable](g Graph[N], starts []N, isGoal func(N) bool, heuristic Heuristic[N]) Result[N] {
+	result := newResult[N]()
+	frontier := NewPriorityQueue[N]()
+
+	estimate := func(n N, cost int) int {
+		if heuristic == nil {
+			return cost
+		}
+		return cost + heuristic(n)
+	}
+
+	for _, start := range starts {
+		result.Costs[start] = 0
+		frontier.Push(start, estimate(start, 0))
+	}
+
+	closed := make(map[N]bool)
+	for frontier.Len() > 0 {
+		current, _ := frontier.Pop()
+		if closed[current] {
+			// a stale entry; this node was already reached more cheaply
+			continue
+		}
+		closed[current] = true
+
+		if isGoal != nil && isGoal(current) {
+			result.Goal = current
+			result.Found = true
+			break
+		}
+
+		for _, edge := range g.Edges(current) {
+			newCost := result.Costs[current] + edge.Cost
+			if foundCost, found := result.Costs[edge.To]; !found || newCost < foundCost {
+				result.Costs[edge.To] = newCost
+				result.cameFrom[edge.To] = current
+				delete(closed, edge.To)
+				frontier.Push(edge.To, estimate(edge.To, newCost))
+			}
+		}
+	}
+
+	return result
+}
+
+// BFS finds the path with the fewest edges from any of the starts to a node
+// matching isGoal, ignoring edge costs. If isGoal is nil, the search explores
+// every reachable node.
+func BFS[N comparable](g Graph[N], starts []N, isGoal func(N) bool) Result[N] {
+	result := newResult[N]()
+	frontier := make([]N, 0, len(starts))
+
+	for _, start := range starts {
+		if _, seen := result.Costs[start]; !seen {
+			result.Costs[start] = 0
+			frontier = append(frontier, start)
+		}
+	}
+
+	for len(frontier) > 0 {
+		current := frontier[0]
+		frontier = frontier[1:]
+
+		if isGoal != nil && isGoal(current) {
+			result.Goal = current
+			result.Found = true
+			break
+		}
+
+		for _, edge := range g.Edges(current) {
+			if _, seen := result.Costs[edge.To]; seen {
+				continue
+			}
+			result.Costs[edge.To] = result.Costs[current] + 1
+			result.cameFrom[edge.To] = current
+			frontier = append(frontier, edge.To)
+		}
+	}
+
+	return result
+}
+
+// AllPairs finds the cost of the cheapest path from each of the given nodes to
+// every node reachable from it. Pairs with no path between them are left out.
+func AllPairs[N comparable](g Graph[N], nodes []N) map[N]map[N]int {
+	distances := make(map[N]map[N]int, len(nodes))
+	for _, n := range nodes {
+		distances[n] = Dijkstra(g, []N{n}, nil).Costs
+	}
+	return distances
+}
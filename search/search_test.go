@@ -0,0 +1,205 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testGraph is a small weighted graph. E can't be reached from anything else,
+// and nothing leads back to F.
+var testGraph = GraphFunc[string](func(n string) []Edge[string] {
+	return map[string][]Edge[string]{
+		"A": {{To: "B", Cost: 1}, {To: "C", Cost: 4}},
+		"B": {{To: "C", Cost: 1}, {To: "D", Cost: 5}},
+		"C": {{To: "D", Cost: 1}},
+		"F": {{To: "A", Cost: 1}},
+	}[n]
+})
+
+// diamond has two equally short paths from S to T, through L or R
+func diamond(first, second string) GraphFunc[string] {
+	return func(n string) []Edge[string] {
+		switch n {
+		case "S":
+			{
+				return []Edge[string]{{To: first, Cost: 1}, {To: second, Cost: 1}}
+			}
+		case "L", "R":
+			{
+				return []Edge[string]{{To: "T", Cost: 1}}
+			}
+		default:
+			{
+				return nil
+			}
+		}
+	}
+}
+
+type searchFunc func(g Graph[string], starts []string, isGoal func(string) bool) Result[string]
+
+var searches = map[string]searchFunc{
+	"Dijkstra": Dijkstra[string],
+	"BFS":      BFS[string],
+	"AStar": func(g Graph[string], starts []string, isGoal func(string) bool) Result[string] {
+		return AStar(g, starts, isGoal, func(string) int { return 0 })
+	},
+}
+
+func is(goal string) func(string) bool {
+	return func(n string) bool { return n == goal }
+}
+
+func TestCheapestPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		search searchFunc
+		cost   int
+		path   []string
+	}{
+		// The path with the fewest edges costs more
+		{"BFS", BFS[string], 2, []string{"A", "B", "D"}},
+		{"Dijkstra", Dijkstra[string], 3, []string{"A", "B", "C", "D"}},
+		{"AStar", searches["AStar"], 3, []string{"A", "B", "C", "D"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := test.search(testGraph, []string{"A"}, is("D"))
+			if !result.Found || result.Goal != "D" {
+				t.Fatalf("got goal %q (found %v), want D", result.Goal, result.Found)
+			}
+			if cost, ok := result.Cost("D"); !ok || cost != test.cost {
+				t.Errorf("got cost %d (%v), want %d", cost, ok, test.cost)
+			}
+			if path := result.Path("D"); !reflect.DeepEqual(path, test.path) {
+				t.Errorf("got path %v, want %v", path, test.path)
+			}
+		})
+	}
+}
+
+func TestUnreachableGoal(t *testing.T) {
+	for name, search := range searches {
+		t.Run(name, func(t *testing.T) {
+			result := search(testGraph, []string{"A"}, is("E"))
+			if result.Found {
+				t.Errorf("found unreachable goal %q", result.Goal)
+			}
+			if _, ok := result.Cost("E"); ok {
+				t.Error("got a cost for an unreachable node")
+			}
+			if path := result.Path("E"); path != nil {
+				t.Errorf("got path %v to an unreachable node", path)
+			}
+			// Everything reachable was explored looking for it
+			if len(result.Costs) != 4 {
+				t.Errorf("reached %d nodes, want 4", len(result.Costs))
+			}
+		})
+	}
+}
+
+func TestStartIsGoal(t *testing.T) {
+	for name, search := range searches {
+		t.Run(name, func(t *testing.T) {
+			result := search(testGraph, []string{"B"}, is("B"))
+			if !result.Found || result.Goal != "B" {
+				t.Fatalf("got goal %q (found %v), want B", result.Goal, result.Found)
+			}
+			if cost, _ := result.Cost("B"); cost != 0 {
+				t.Errorf("got cost %d, want 0", cost)
+			}
+			if path := result.Path("B"); !reflect.DeepEqual(path, []string{"B"}) {
+				t.Errorf("got path %v, want [B]", path)
+			}
+		})
+	}
+}
+
+func TestMultipleStarts(t *testing.T) {
+	for name, search := range searches {
+		t.Run(name, func(t *testing.T) {
+			result := search(testGraph, []string{"F", "C"}, is("D"))
+			if path := result.Path("D"); !reflect.DeepEqual(path, []string{"C", "D"}) {
+				t.Errorf("got path %v, want [C D]", path)
+			}
+		})
+	}
+}
+
+func TestTiesFollowEdgeOrder(t *testing.T) {
+	for name, search := range searches {
+		t.Run(name, func(t *testing.T) {
+			for _, first := range []string{"L", "R"} {
+				second := map[string]string{"L": "R", "R": "L"}[first]
+				result := search(diamond(first, second), []string{"S"}, is("T"))
+				want := []string{"S", first, "T"}
+				if path := result.Path("T"); !reflect.DeepEqual(path, want) {
+					t.Errorf("got path %v, want %v", path, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHeuristic(t *testing.T) {
+	line := Unweighted[int](func(n int) []int { return []int{n - 1, n + 1} })
+	distance := func(n int) int {
+		if n > 10 {
+			return n - 10
+		}
+		return 10 - n
+	}
+
+	dijkstra := Dijkstra[int](line, []int{0}, func(n int) bool { return n == 10 })
+	astar := AStar[int](line, []int{0}, func(n int) bool { return n == 10 }, distance)
+	if cost, _ := astar.Cost(10); cost != 10 {
+		t.Errorf("got cost %d, want 10", cost)
+	}
+	if len(astar.Path(10)) != 11 {
+		t.Errorf("got path %v", astar.Path(10))
+	}
+	// Dijkstra explores just as far in the wrong direction
+	if len(astar.Costs) >= len(dijkstra.Costs) {
+		t.Errorf("A* reached %d nodes, no fewer than Dijkstra's %d", len(astar.Costs), len(dijkstra.Costs))
+	}
+}
+
+func TestInconsistentHeuristic(t *testing.T) {
+	g := GraphFunc[string](func(n string) []Edge[string] {
+		return map[string][]Edge[string]{
+			"S": {{To: "A", Cost: 1}, {To: "B", Cost: 1}},
+			"A": {{To: "C", Cost: 1}},
+			"B": {{To: "C", Cost: 2}},
+			"C": {{To: "G", Cost: 3}},
+		}[n]
+	})
+	// Never more than the real cost, but A looks much further from the goal
+	// than C does, so C is first reached the expensive way, through B
+	h := func(n string) int {
+		if n == "A" {
+			return 3
+		}
+		return 0
+	}
+
+	result := AStar[string](g, []string{"S"}, is("G"), h)
+	if cost, _ := result.Cost("G"); cost != 5 {
+		t.Errorf("got cost %d, want 5", cost)
+	}
+	if path, want := result.Path("G"), []string{"S", "A", "C", "G"}; !reflect.DeepEqual(path, want) {
+		t.Errorf("got path %v, want %v", path, want)
+	}
+}
+
+func TestAllPairs(t *testing.T) {
+	got := AllPairs[string](testGraph, []string{"A", "E", "F"})
+	want := map[string]map[string]int{
+		"A": {"A": 0, "B": 1, "C": 2, "D": 3},
+		"E": {"E": 0},
+		"F": {"F": 0, "A": 1, "B": 2, "C": 3, "D": 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
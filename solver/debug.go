@@ -0,0 +1,29 @@
+package solver
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// DEBUG is for a handful of diagnostic lines per part, such as a detected
+	// cycle
+	DEBUG = 1
+	// TRACE is for a line per step of a simulation
+	TRACE = 2
+)
+
+// Verbosity controls how much diagnostic output solvers write. It defaults to
+// none, so that stdout only ever holds answers.
+var Verbosity = 0
+
+// DebugOutput is where diagnostic output is written
+var DebugOutput io.Writer = os.Stderr
+
+// Debugf writes diagnostic output if Verbosity is at least level
+func Debugf(level int, format string, a ...any) {
+	if Verbosity >= level {
+		fmt.Fprintf(DebugOutput, format, a...)
+	}
+}
@@ -0,0 +1,90 @@
+// Package solver defines the interface shared by every day's puzzle solution,
+// so that they can be run and compared from a single place.
+package solver
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrNoPart is returned when a day has no puzzle for the requested part
+var ErrNoPart = errors.New("no puzzle for this part")
+
+type Solver interface {
+	// Solve reads a puzzle input from r and returns the answer to the given
+	// part (1 or 2)
+	Solve(part int, r io.Reader) (string, error)
+}
+
+// Func solves one part of a day's puzzle
+type Func func(r io.Reader) (string, error)
+
+// Day adapts a day's Part1 and Part2 functions to the Solver interface.
+// Malformed input is reported as an error by every day; any panic is still
+// recovered and returned as an error, but only as a safety net against bugs
+type Day struct {
+	Part1 Func
+	Part2 Func
+}
+
+func (d Day) Solve(part int, r io.Reader) (answer string, err error) {
+	var f Func
+	switch part {
+	case 1:
+		f = d.Part1
+	case 2:
+		f = d.Part2
+	default:
+		return "", fmt.Errorf("invalid part %d", part)
+	}
+	if f == nil {
+		return "", ErrNoPart
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	return f(r)
+}
+
+// Main reads the input file named by the command line, solves both parts of it
+// with s and prints the answers. It is used by each day's main package. Days
+// with flags of their own parse them first; the input file is whatever is left
+// over, so flags set to their defaults don't get in the way.
+func Main(s Solver) {
+	if !flag.Parsed() {
+		flag.Usage = func() {
+			fmt.Fprintf(os.Stderr, "usage: %s <input file>\n", os.Args[0])
+		}
+		flag.Parse()
+	}
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dat, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for part := 1; part <= 2; part++ {
+		answer, err := s.Solve(part, strings.NewReader(string(dat)))
+		if errors.Is(err, ErrNoPart) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "part %d: %s\n", part, err)
+			os.Exit(1)
+		}
+		fmt.Printf("part %d: %s\n", part, answer)
+	}
+}
@@ -0,0 +1,130 @@
+package submit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// HISTORY_ENV overrides where the history of attempts is kept
+const HISTORY_ENV = "AOC_HISTORY"
+
+const DEFAULT_HISTORY_PATH = "submissions.json"
+
+var (
+	// ErrKnownWrong is returned for an answer the history shows is wrong
+	ErrKnownWrong = errors.New("answer is known to be wrong")
+	// ErrAlreadySolved is returned for a part the history shows is solved
+	ErrAlreadySolved = errors.New("part is already solved")
+)
+
+// Attempt is a single submitted answer and what the site said about it
+type Attempt struct {
+	Day     int       `json:"day"`
+	Part    int       `json:"part"`
+	Answer  string    `json:"answer"`
+	Outcome Outcome   `json:"outcome"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// History is every attempt made so far, saved as JSON
+type History struct {
+	path     string
+	Attempts []Attempt
+}
+
+// HistoryPath returns where the history is kept, according to the environment
+func HistoryPath() string {
+	if path := os.Getenv(HISTORY_ENV); path != "" {
+		return path
+	}
+	return DEFAULT_HISTORY_PATH
+}
+
+// LoadHistory reads the history at path. A missing file is an empty history.
+func LoadHistory(path string) (*History, error) {
+	h := &History{path: path, Attempts: make([]Attempt, 0)}
+
+	dat, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(dat, &h.Attempts); err != nil {
+		return nil, fmt.Errorf("reading history %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// Save writes the history back to the file it was loaded from
+func (h *History) Save() error {
+	dat, err := json.MarshalIndent(h.Attempts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(h.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(h.path, append(dat, '\n'), 0o644)
+}
+
+// Record adds an attempt to the history
+func (h *History) Record(day int, part int, answer string, res Response) {
+	h.Attempts = append(h.Attempts, Attempt{
+		Day:     day,
+		Part:    part,
+		Answer:  answer,
+		Outcome: res.Outcome,
+		Message: res.Message,
+		Time:    time.Now().UTC(),
+	})
+}
+
+// Check returns an error if the history already shows that submitting answer
+// would be pointless: the part is solved, the same answer was rejected, or an
+// earlier "too high"/"too low" rules the answer out.
+func (h *History) Check(day int, part int, answer string) error {
+	value, numeric := parseAnswer(answer)
+	for _, a := range h.Attempts {
+		if a.Day != day || a.Part != part {
+			continue
+		}
+		if a.Outcome == CORRECT {
+			return fmt.Errorf("%w with %s", ErrAlreadySolved, a.Answer)
+		}
+		if !a.Outcome.IsWrong() {
+			continue
+		}
+		if a.Answer == answer {
+			return fmt.Errorf("%w: %s was %s", ErrKnownWrong, answer, a.Outcome)
+		}
+
+		bound, ok := parseAnswer(a.Answer)
+		if !numeric || !ok {
+			continue
+		}
+		if a.Outcome == TOO_HIGH && value >= bound {
+			return fmt.Errorf("%w: %s was too high", ErrKnownWrong, a.Answer)
+		}
+		if a.Outcome == TOO_LOW && value <= bound {
+			return fmt.Errorf("%w: %s was too low", ErrKnownWrong, a.Answer)
+		}
+	}
+	return nil
+}
+
+func parseAnswer(answer string) (int64, bool) {
+	value, err := strconv.ParseInt(answer, 10, 64)
+	return value, err == nil
+}
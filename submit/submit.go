@@ -0,0 +1,172 @@
+// Package submit posts answers to Advent of Code and keeps a local history of
+// every attempt, so a wrong answer is never submitted twice.
+package submit
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FaideWW/aoc-2022/inputs"
+)
+
+type Outcome string
+
+const (
+	CORRECT   Outcome = "correct"
+	TOO_HIGH  Outcome = "too high"
+	TOO_LOW   Outcome = "too low"
+	INCORRECT Outcome = "incorrect"
+	// WAIT means the answer wasn't checked because the last attempt was too
+	// recent
+	WAIT Outcome = "wait"
+	// WRONG_LEVEL means the part was already solved, or isn't unlocked yet
+	WRONG_LEVEL Outcome = "wrong level"
+	UNKNOWN     Outcome = "unknown"
+)
+
+// IsWrong reports whether the answer was checked and rejected
+func (o Outcome) IsWrong() bool {
+	return o == TOO_HIGH || o == TOO_LOW || o == INCORRECT
+}
+
+// Response is what the site said about a submitted answer
+type Response struct {
+	Outcome Outcome
+	// Wait is how long the site asked us to wait before the next attempt
+	Wait    time.Duration
+	Message string
+}
+
+var (
+	articlePattern = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagPattern     = regexp.MustCompile(`<[^>]+>`)
+	spacePattern   = regexp.MustCompile(`\s+`)
+	// e.g. "You have 1m 5s left to wait."
+	leftToWaitPattern = regexp.MustCompile(`You have (?:(\d+)m ?)?(?:(\d+)s )?left to wait`)
+	// e.g. "please wait 5 minutes before trying again", "please wait one minute"
+	pleaseWaitPattern = regexp.MustCompile(`(?i)please wait (\w+) minutes?`)
+)
+
+// ParseResponse reads the HTML page the site returns after a submission
+func ParseResponse(body string) Response {
+	message := body
+	if match := articlePattern.FindStringSubmatch(body); match != nil {
+		message = match[1]
+	}
+	message = tagPattern.ReplaceAllString(message, "")
+	message = strings.TrimSpace(spacePattern.ReplaceAllString(message, " "))
+
+	res := Response{Outcome: UNKNOWN, Message: message}
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		{
+			res.Outcome = CORRECT
+		}
+	case strings.Contains(message, "That's not the right answer"):
+		{
+			res.Outcome = INCORRECT
+			if strings.Contains(message, "too high") {
+				res.Outcome = TOO_HIGH
+			} else if strings.Contains(message, "too low") {
+				res.Outcome = TOO_LOW
+			}
+			if match := pleaseWaitPattern.FindStringSubmatch(message); match != nil {
+				res.Wait = time.Duration(parseCount(match[1])) * time.Minute
+			}
+		}
+	case strings.Contains(message, "You gave an answer too recently"):
+		{
+			res.Outcome = WAIT
+			if match := leftToWaitPattern.FindStringSubmatch(message); match != nil {
+				minutes, _ := strconv.Atoi(match[1])
+				seconds, _ := strconv.Atoi(match[2])
+				res.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+			}
+		}
+	case strings.Contains(message, "You don't seem to be solving the right level"):
+		{
+			res.Outcome = WRONG_LEVEL
+		}
+	}
+	return res
+}
+
+// parseCount reads a count the site may spell out in words
+func parseCount(s string) int {
+	words := []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+	for i, word := range words {
+		if strings.EqualFold(s, word) {
+			return i
+		}
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// Client posts answers to the site (or a stand-in for it)
+type Client struct {
+	BaseURL    string
+	Session    string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client configured from the same environment variables
+// used to download inputs
+func NewClient() *Client {
+	baseURL := os.Getenv(inputs.BASE_URL_ENV)
+	if baseURL == "" {
+		baseURL = inputs.DEFAULT_BASE_URL
+	}
+	return &Client{
+		BaseURL:    baseURL,
+		Session:    os.Getenv(inputs.SESSION_ENV),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// Submit posts an answer for one part of a day's puzzle
+func (c *Client) Submit(day int, part int, answer string) (Response, error) {
+	if c.Session == "" {
+		return Response{}, inputs.ErrNoSession
+	}
+
+	endpoint := fmt.Sprintf("%s/%d/day/%d/answer", strings.TrimRight(c.BaseURL, "/"), inputs.YEAR, day)
+	form := url.Values{
+		"level":  {strconv.Itoa(part)},
+		"answer": {answer},
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Response{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", inputs.USER_AGENT)
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Response{}, fmt.Errorf("submitting day %d part %d: %s: %s", day, part, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return ParseResponse(string(body)), nil
+}
@@ -0,0 +1,120 @@
+package submit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func page(article string) string {
+	return "<html><body><main><article><p>" + article + "</p></article></main></body></html>"
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		body    string
+		outcome Outcome
+		wait    time.Duration
+	}{
+		{page(`That's the right answer!  You are <em>one gold star</em> closer to saving your vacation.`), CORRECT, 0},
+		{page(`That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data; please wait one minute before trying again. [<a href="/2022/day/22">Return to Day 22</a>]`), TOO_HIGH, time.Minute},
+		{page(`That's not the right answer; your answer is too low.  Please wait 5 minutes before trying again.`), TOO_LOW, 5 * time.Minute},
+		{page(`That's not the right answer.  If you're stuck, make sure you're using the full input data.`), INCORRECT, 0},
+		{page(`You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 1m 5s left to wait.`), WAIT, time.Minute + 5*time.Second},
+		{page(`You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 42s left to wait.`), WAIT, 42 * time.Second},
+		{page(`You don't seem to be solving the right level.  Did you already complete it?`), WRONG_LEVEL, 0},
+		{"<html>maintenance</html>", UNKNOWN, 0},
+	}
+
+	for _, test := range tests {
+		res := ParseResponse(test.body)
+		if res.Outcome != test.outcome || res.Wait != test.wait {
+			t.Errorf("ParseResponse(%q) = %s, %s; want %s, %s", res.Message, res.Outcome, res.Wait, test.outcome, test.wait)
+		}
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2022/day/25/answer" {
+			http.NotFound(w, r)
+			return
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "secret" {
+			http.Error(w, "not logged in", http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("level") == "1" && r.FormValue("answer") == "2=-1=0" {
+			w.Write([]byte(page("That's the right answer!")))
+		} else {
+			w.Write([]byte(page("That's not the right answer.")))
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{BaseURL: server.URL, Session: "secret"}
+	res, err := c.Submit(25, 1, "2=-1=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Outcome != CORRECT {
+		t.Errorf("right answer: got %s", res.Outcome)
+	}
+
+	res, err = c.Submit(25, 1, "1=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Outcome != INCORRECT {
+		t.Errorf("wrong answer: got %s", res.Outcome)
+	}
+
+	c.Session = ""
+	if _, err := c.Submit(25, 1, "1="); err == nil {
+		t.Error("expected an error without a session")
+	}
+}
+
+func TestHistoryCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	h, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Record(22, 1, "5000", Response{Outcome: TOO_HIGH})
+	h.Record(22, 1, "1000", Response{Outcome: TOO_LOW})
+	h.Record(22, 1, "abc", Response{Outcome: INCORRECT})
+	h.Record(25, 1, "2=-1=0", Response{Outcome: CORRECT})
+	if err := h.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// make sure the checks survive a round trip through the file
+	h, err = LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		day    int
+		part   int
+		answer string
+		err    error
+	}{
+		{22, 1, "5000", ErrKnownWrong},
+		{22, 1, "6000", ErrKnownWrong},
+		{22, 1, "999", ErrKnownWrong},
+		{22, 1, "abc", ErrKnownWrong},
+		{22, 1, "3000", nil},
+		{22, 2, "5000", nil},
+		{25, 1, "1=", ErrAlreadySolved},
+	}
+	for _, test := range tests {
+		if err := h.Check(test.day, test.part, test.answer); !errors.Is(err, test.err) {
+			t.Errorf("Check(%d, %d, %q) = %v, want %v", test.day, test.part, test.answer, err, test.err)
+		}
+	}
+}